@@ -0,0 +1,150 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Type string
+
+const (
+	Credit   Type = "credit"
+	Checking Type = "checking"
+	Savings  Type = "savings"
+	Cash     Type = "cash"
+)
+
+type Account struct {
+	// Name is what is written to the sheet's account column.
+	Name        string `json:"name"`
+	Institution string `json:"institution"`
+	Type        Type   `json:"type"`
+	// Last4 is the last four digits of the card or account number.
+	Last4 string `json:"last4"`
+	Owner string `json:"owner"`
+	// FilePatterns are shell patterns, as in filepath.Match, for the names
+	// of the files downloaded from the account, e.g. "Chase1234_Activity*".
+	// They are matched against the base name, ignoring case.
+	FilePatterns []string `json:"file_patterns"`
+	// OFXIDs are the account IDs the account's OFX files give.
+	OFXIDs []string `json:"ofx_ids"`
+}
+
+// List holds the configured accounts in the order of the config file.
+type List []Account
+
+type accountsFile struct {
+	Accounts []Account `json:"accounts"`
+}
+
+func ListFromFile(fileName string) (List, error) {
+	accountsFileHandle, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer accountsFileHandle.Close()
+
+	accountsFileBytes, err := io.ReadAll(accountsFileHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var af accountsFile
+	if err = json.Unmarshal(accountsFileBytes, &af); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal accounts: %w", err)
+	}
+	l := List(af.Accounts)
+	if err = l.validate(); err != nil {
+		return nil, fmt.Errorf("invalid accounts: %w", err)
+	}
+	return l, nil
+}
+
+func (l List) validate() error {
+	names := map[string]bool{}
+	for _, a := range l {
+		if a.Name == "" {
+			return errors.New("found an account without a name")
+		}
+		if names[a.Name] {
+			return fmt.Errorf("found duplicate account name: %s", a.Name)
+		}
+		names[a.Name] = true
+		switch a.Type {
+		case Credit, Checking, Savings, Cash:
+		default:
+			return fmt.Errorf("account %s has unknown type '%s'", a.Name, a.Type)
+		}
+		for _, pattern := range a.FilePatterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("account %s has invalid file pattern '%s': %w", a.Name, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (l List) ByName(name string) (Account, bool) {
+	for _, a := range l {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Account{}, false
+}
+
+// ForFile returns the first account with a file pattern matching fileName.
+func (l List) ForFile(fileName string) (Account, bool) {
+	base := strings.ToLower(filepath.Base(fileName))
+	for _, a := range l {
+		for _, pattern := range a.FilePatterns {
+			if ok, _ := filepath.Match(strings.ToLower(pattern), base); ok {
+				return a, true
+			}
+		}
+	}
+	return Account{}, false
+}
+
+// ForOFXID returns the account an OFX account ID belongs to. Accounts
+// without OFX IDs are matched on the last four digits, since banks often
+// mask the rest of the number.
+func (l List) ForOFXID(id string) (Account, bool) {
+	if id == "" {
+		return Account{}, false
+	}
+	for _, a := range l {
+		for _, ofxID := range a.OFXIDs {
+			if ofxID == id {
+				return a, true
+			}
+		}
+	}
+	for _, a := range l {
+		if len(a.OFXIDs) == 0 && a.Last4 != "" && strings.HasSuffix(id, a.Last4) {
+			return a, true
+		}
+	}
+	return Account{}, false
+}
+
+func (l List) Names() []string {
+	names := make([]string, 0, len(l))
+	for _, a := range l {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// Label describes the account in menus, e.g. "Freedom (Chase credit ...1234)".
+func (a Account) Label() string {
+	details := strings.TrimSpace(a.Institution + " " + string(a.Type))
+	if a.Last4 != "" {
+		details += " ..." + a.Last4
+	}
+	return fmt.Sprintf("%s (%s)", a.Name, details)
+}
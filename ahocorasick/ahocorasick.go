@@ -0,0 +1,117 @@
+// Package ahocorasick finds every occurrence of a fixed set of patterns in a
+// text in one pass, however many patterns there are.
+package ahocorasick
+
+// Match is an occurrence of the pattern with index Pattern at text[Start:End].
+type Match struct {
+	Pattern    int
+	Start, End int
+}
+
+// Matcher is an Aho-Corasick automaton over a set of patterns. It ignores
+// ASCII case; other bytes must match exactly.
+type Matcher struct {
+	nodes   []node
+	lengths []int
+}
+
+type node struct {
+	next map[byte]int
+	fail int
+	// outputs are the patterns ending at this node, including those ending
+	// at nodes reached by following fail links.
+	outputs []int
+}
+
+// New compiles patterns into a Matcher. Empty patterns never match.
+func New(patterns []string) *Matcher {
+	m := &Matcher{nodes: []node{{}}, lengths: make([]int, len(patterns))}
+	for i, p := range patterns {
+		m.lengths[i] = len(p)
+		if p == "" {
+			continue
+		}
+		current := 0
+		for j := 0; j < len(p); j++ {
+			b := lower(p[j])
+			next, ok := m.nodes[current].next[b]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, node{})
+				if m.nodes[current].next == nil {
+					m.nodes[current].next = map[byte]int{}
+				}
+				m.nodes[current].next[b] = next
+			}
+			current = next
+		}
+		m.nodes[current].outputs = append(m.nodes[current].outputs, i)
+	}
+	m.linkFailures()
+	return m
+}
+
+// linkFailures points every node at the node for its longest proper suffix
+// that is also in the trie, visiting nodes breadth first so that shorter
+// suffixes are linked before they are needed.
+func (m *Matcher) linkFailures() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[current].next {
+			fail := m.nodes[current].fail
+			for {
+				if next, ok := m.nodes[fail].next[b]; ok {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			inherited := m.nodes[m.nodes[child].fail].outputs
+			if len(inherited) > 0 {
+				outputs := make([]int, 0, len(m.nodes[child].outputs)+len(inherited))
+				outputs = append(outputs, m.nodes[child].outputs...)
+				m.nodes[child].outputs = append(outputs, inherited...)
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// FindAll returns every occurrence of every pattern in text, ordered by where
+// they end.
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	current := 0
+	for i := 0; i < len(text); i++ {
+		b := lower(text[i])
+		for {
+			if next, ok := m.nodes[current].next[b]; ok {
+				current = next
+				break
+			}
+			if current == 0 {
+				break
+			}
+			current = m.nodes[current].fail
+		}
+		for _, p := range m.nodes[current].outputs {
+			matches = append(matches, Match{Pattern: p, Start: i + 1 - m.lengths[p], End: i + 1})
+		}
+	}
+	return matches
+}
+
+func lower(b byte) byte {
+	if 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package ahocorasick
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// findAllNaive finds the same matches as FindAll by trying every pattern at
+// every position, ignoring ASCII case only.
+func findAllNaive(patterns []string, text string) []Match {
+	var matches []Match
+	lowerText := asciiLower(text)
+	for p, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		lowerPattern := asciiLower(pattern)
+		for start := 0; start+len(pattern) <= len(text); start++ {
+			if strings.HasPrefix(lowerText[start:], lowerPattern) {
+				matches = append(matches, Match{Pattern: p, Start: start, End: start + len(pattern)})
+			}
+		}
+	}
+	return matches
+}
+
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i := range b {
+		b[i] = lower(b[i])
+	}
+	return string(b)
+}
+
+func sortMatches(matches []Match) {
+	sort.Slice(matches, func(x, y int) bool {
+		if matches[x].End != matches[y].End {
+			return matches[x].End < matches[y].End
+		}
+		return matches[x].Pattern < matches[y].Pattern
+	})
+}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+	}{
+		{"textbook", []string{"he", "she", "his", "hers"}, "ushers"},
+		{"overlapping", []string{"a", "aa", "aaa"}, "aaaa"},
+		{"case", []string{"Circle K", "wegmans"}, "WEGMANS and circle k"},
+		{"suffix of another", []string{"raleigh beer gar", "beer", "gar"}, "TST* THE RALEIGH BEER GARDEN"},
+		{"fail chain", []string{"abcd", "bcx", "cxy"}, "abcxyz abcd"},
+		{"no match", []string{"wegmans"}, "HARRIS TEETER"},
+		{"empty pattern", []string{"", "k"}, "circle k"},
+		{"duplicate patterns", []string{"bar", "bar"}, "milk bar"},
+		{"empty text", []string{"a"}, ""},
+		{"non-ASCII", []string{"café", "CAFÉ"}, "Le Café"},
+	}
+	for _, test := range tests {
+		got := New(test.patterns).FindAll(test.text)
+		for i := 1; i < len(got); i++ {
+			if got[i].End < got[i-1].End {
+				t.Errorf("%s: matches are not ordered by end: %v", test.name, got)
+			}
+		}
+		expected := findAllNaive(test.patterns, test.text)
+		sortMatches(got)
+		sortMatches(expected)
+		if len(got) != len(expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, expected, got)
+			continue
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("%s: expected %v, got %v", test.name, expected, got)
+				break
+			}
+		}
+	}
+}
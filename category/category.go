@@ -0,0 +1,183 @@
+package category
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Type string
+
+const (
+	Expense  Type = "expense"
+	Income   Type = "income"
+	Transfer Type = "transfer"
+	// Skip categories mark transactions to leave out of the statement.
+	Skip Type = "skip"
+)
+
+type Category struct {
+	// Name is what is shown to the user and written to the sheet.
+	Name string `json:"name"`
+	// Key is how keywords.json refers to the category.
+	Key    string `json:"key"`
+	Type   Type   `json:"type"`
+	Parent string `json:"parent"`
+	// Order is the position in the category menu, lowest first.
+	Order int `json:"order"`
+	// Color is the background of the category's cells in the sheet, as
+	// #RRGGBB.
+	Color string `json:"color"`
+}
+
+// List holds the configured categories sorted by Order.
+type List []Category
+
+type categoriesFile struct {
+	Categories []Category `json:"categories"`
+}
+
+func ListFromFile(fileName string) (List, error) {
+	categoriesFileHandle, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer categoriesFileHandle.Close()
+
+	categoriesFileBytes, err := io.ReadAll(categoriesFileHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var cf categoriesFile
+	if err = json.Unmarshal(categoriesFileBytes, &cf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal categories: %w", err)
+	}
+	l := List(cf.Categories)
+	if err = l.validate(); err != nil {
+		return nil, fmt.Errorf("invalid categories: %w", err)
+	}
+	sort.SliceStable(l, func(x, y int) bool {
+		return l[x].Order < l[y].Order
+	})
+	return l, nil
+}
+
+func (l List) validate() error {
+	if len(l) == 0 {
+		return errors.New("no categories defined")
+	}
+	names := map[string]bool{}
+	keys := map[string]bool{}
+	for _, c := range l {
+		if c.Name == "" || c.Key == "" {
+			return fmt.Errorf("category %+v needs both a name and a key", c)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("found duplicate category name: %s", c.Name)
+		}
+		if keys[c.Key] {
+			return fmt.Errorf("found duplicate category key: %s", c.Key)
+		}
+		names[c.Name] = true
+		keys[c.Key] = true
+		switch c.Type {
+		case Expense, Income, Transfer, Skip:
+		default:
+			return fmt.Errorf("category %s has unknown type '%s'", c.Name, c.Type)
+		}
+		if c.Color != "" {
+			if _, _, _, err := c.RGB(); err != nil {
+				return fmt.Errorf("category %s has invalid color: %w", c.Name, err)
+			}
+		}
+	}
+	for _, c := range l {
+		if c.Parent != "" && !names[c.Parent] {
+			return fmt.Errorf("category %s has unknown parent %s", c.Name, c.Parent)
+		}
+	}
+	return nil
+}
+
+func (l List) ByKey(key string) (Category, bool) {
+	for _, c := range l {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+func (l List) ByName(name string) (Category, bool) {
+	for _, c := range l {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// FirstOfType returns the first category of type t in menu order.
+func (l List) FirstOfType(t Type) (Category, bool) {
+	for _, c := range l {
+		if c.Type == t {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// IsSkip reports whether name is a category of type Skip.
+func (l List) IsSkip(name string) bool {
+	c, ok := l.ByName(name)
+	return ok && c.Type == Skip
+}
+
+// Selectable returns the categories a transaction can be filed under, i.e.
+// all except skip categories, in menu order.
+func (l List) Selectable() List {
+	selectable := make(List, 0, len(l))
+	for _, c := range l {
+		if c.Type != Skip {
+			selectable = append(selectable, c)
+		}
+	}
+	return selectable
+}
+
+func (l List) Names() []string {
+	names := make([]string, 0, len(l))
+	for _, c := range l {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// Label is the category's name as shown in menus, including its parent.
+func (c Category) Label() string {
+	if c.Parent == "" {
+		return c.Name
+	}
+	return c.Parent + " > " + c.Name
+}
+
+// RGB returns the components of Color between 0 and 1.
+func (c Category) RGB() (r, g, b float64, err error) {
+	hex := strings.TrimPrefix(c.Color, "#")
+	if len(hex) != 6 {
+		return 0, 0, 0, fmt.Errorf("'%s' is not of the form #RRGGBB", c.Color)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to parse '%s' as hex: %w", c.Color, err)
+	}
+	r = float64(value>>16&0xff) / 255
+	g = float64(value>>8&0xff) / 255
+	b = float64(value&0xff) / 255
+	return r, g, b, nil
+}
@@ -0,0 +1,240 @@
+package chase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/cleanprint"
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// dateLayout is the layout of every date in Chase exports.
+const dateLayout = date.USLayout
+
+var expectedCheckingColumnNames []string = []string{
+	"Details",
+	"Posting Date",
+	"Description",
+	"Amount",
+	"Type",
+	"Balance",
+	"Check or Slip #",
+}
+
+// CheckingTransaction is a row of a Chase checking or debit account export.
+// Unlike the credit card export, which mappings/chase-credit.json describes,
+// Amount keeps the sign Chase gives it: negative for money leaving the
+// account and positive for money arriving.
+type CheckingTransaction struct {
+	Details     string
+	PostingDate date.Date
+	Description string
+	Amount      money.Amount
+	ItemType    string
+	Balance     *money.Amount
+	CheckNumber string
+	// Row is the row of the export the transaction was read from.
+	Row int
+}
+
+type CheckingStatement []CheckingTransaction
+
+func CsvContentsToCheckingStatement(csvContents [][]string) (s CheckingStatement, err error) {
+	if err := validateCheckingHeaderRow(csvContents[0]); err != nil {
+		return nil, fmt.Errorf("failed to validate header row: %w", err)
+	}
+	csvContents = csvContents[1:]
+
+	s = make([]CheckingTransaction, 0, len(csvContents))
+	for i, row := range csvContents {
+		t, err := csvRowToCheckingTransaction(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert row %d to Chase checking transaction: %w", i+2, err)
+		}
+		t.Row = i + 2
+		s = append(s, t)
+	}
+	return s, nil
+}
+
+func validateCheckingHeaderRow(headerRow []string) error {
+	headerRow = trimTrailingEmptyCells(headerRow, len(expectedCheckingColumnNames))
+	if len(headerRow) != len(expectedCheckingColumnNames) {
+		return fmt.Errorf("expected %d columns in header row but got %d", len(expectedCheckingColumnNames), len(headerRow))
+	}
+	for i, columnName := range headerRow {
+		if expectedCheckingColumnNames[i] != columnName {
+			return fmt.Errorf("expected column %d to be named %s but is named %s", i+1, expectedCheckingColumnNames[i], columnName)
+		}
+	}
+	return nil
+}
+
+// trimTrailingEmptyCells drops empty cells past the first minLength cells.
+// Chase checking exports end every data row with a trailing comma, which
+// shows up as an extra empty column.
+func trimTrailingEmptyCells(row []string, minLength int) []string {
+	for len(row) > minLength && strings.TrimSpace(row[len(row)-1]) == "" {
+		row = row[:len(row)-1]
+	}
+	return row
+}
+
+func csvRowToCheckingTransaction(row []string) (t CheckingTransaction, err error) {
+	row = trimTrailingEmptyCells(row, len(expectedCheckingColumnNames))
+	if len(row) != len(expectedCheckingColumnNames) {
+		return t, fmt.Errorf("expected %d columns but got %d", len(expectedCheckingColumnNames), len(row))
+	}
+	amount, err := money.Parse(row[3])
+	if err != nil {
+		return t, fmt.Errorf("failed to parse 'amount' cell: %w", err)
+	}
+	postingDate, err := date.Parse(row[1], dateLayout)
+	if err != nil {
+		return t, fmt.Errorf("failed to parse 'posting date' cell: %w", err)
+	}
+	t = CheckingTransaction{
+		Details:     strings.TrimSpace(row[0]),
+		PostingDate: postingDate,
+		Description: row[2],
+		Amount:      amount,
+		ItemType:    row[4],
+		CheckNumber: strings.TrimSpace(row[6]),
+	}
+	// Chase leaves the balance blank on some rows, e.g. pending items.
+	if balanceCell := strings.TrimSpace(row[5]); balanceCell != "" {
+		balance, err := money.Parse(balanceCell)
+		if err != nil {
+			return t, fmt.Errorf("failed to parse 'balance' cell: %w", err)
+		}
+		t.Balance = &balance
+	}
+	return t, nil
+}
+
+func (t CheckingTransaction) Print() {
+	balance := ""
+	if t.Balance != nil {
+		balance = t.Balance.String()
+	}
+	transactionLines := [][]string{
+		expectedCheckingColumnNames,
+		{
+			t.Details,
+			t.PostingDate.Format(dateLayout),
+			t.Description,
+			t.Amount.String(),
+			t.ItemType,
+			balance,
+			t.CheckNumber,
+		},
+	}
+	cleanprint.Print(transactionLines)
+}
+
+// Fingerprint identifies t across overlapping exports. Two rows with the
+// same fingerprint are the same transaction downloaded twice.
+func (t CheckingTransaction) Fingerprint() string {
+	return fmt.Sprintf("chase-checking|%s|%s|%s|%s|%s|%s",
+		t.Details,
+		t.PostingDate.Format(date.ISOLayout),
+		t.Description,
+		t.Amount.Decimal(),
+		t.ItemType,
+		t.CheckNumber,
+	)
+}
+
+// standardAmount returns the amount in the standard convention, where money
+// spent is positive. A row whose sign disagrees with its Details is reported
+// instead of silently flipped.
+func (t CheckingTransaction) standardAmount() (money.Amount, error) {
+	var isOutflow bool
+	switch strings.ToUpper(t.Details) {
+	case "DEBIT", "CHECK":
+		isOutflow = true
+	case "CREDIT", "DSLIP":
+		isOutflow = false
+	default:
+		return 0, fmt.Errorf("unknown details value '%s'", t.Details)
+	}
+	if t.Amount != 0 && (t.Amount < 0) != isOutflow {
+		return 0, fmt.Errorf("amount %s has the wrong sign for a %s", t.Amount, t.Details)
+	}
+	return t.Amount.Neg(), nil
+}
+
+// Standardize converts the checking statement s into a standard statement.
+func (s CheckingStatement) Standardize() (ss standard.Statement, err error) {
+	ss = make([]standard.Transaction, 0, len(s))
+	for i, t := range s {
+		st, err := t.standardize()
+		if err != nil {
+			return nil, fmt.Errorf("failed to standardize item %d: %w", i, err)
+		}
+		ss = append(ss, st)
+	}
+	return ss, nil
+}
+
+func (t CheckingTransaction) standardize() (st standard.Transaction, err error) {
+	amount, err := t.standardAmount()
+	if err != nil {
+		return st, fmt.Errorf("failed to determine amount: %w", err)
+	}
+	return standard.Transaction{
+		Date:        t.PostingDate,
+		Description: t.Description,
+		Amount:      amount,
+		Balance:     t.Balance,
+		Fingerprint: t.Fingerprint(),
+		Provenance: standard.Provenance{
+			Row:        t.Row,
+			PostedDate: t.PostingDate,
+			ItemType:   t.ItemType,
+			Memo:       checkMemo(t.CheckNumber),
+		},
+	}, nil
+}
+
+// CheckingImporter parses Chase checking and debit account CSV exports. It
+// is not a mapping because it checks each amount's sign against the row's
+// Details and reads the running balance, neither of which mappings can do.
+type CheckingImporter struct{}
+
+func (CheckingImporter) Name() string {
+	return "Chase checking"
+}
+
+func (CheckingImporter) Confidence(headerRow []string, sampleRows [][]string) float64 {
+	if err := validateCheckingHeaderRow(headerRow); err != nil {
+		return 0
+	}
+	for _, row := range sampleRows {
+		if _, err := csvRowToCheckingTransaction(row); err != nil {
+			return 0.5
+		}
+	}
+	return 1
+}
+
+func (CheckingImporter) Import(csvContents [][]string) (standard.Statement, error) {
+	s, err := CsvContentsToCheckingStatement(csvContents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert csv contents to Chase checking statement: %w", err)
+	}
+	ss, err := s.Standardize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to standardize Chase checking statement: %w", err)
+	}
+	return ss, nil
+}
+
+func checkMemo(checkNumber string) string {
+	if checkNumber == "" {
+		return ""
+	}
+	return "Check #" + checkNumber
+}
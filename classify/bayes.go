@@ -0,0 +1,147 @@
+package classify
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// Example is an already categorized transaction to learn from.
+type Example struct {
+	Description string
+	Amount      money.Amount
+	Category    string
+}
+
+// Suggestion is a category the classifier thinks fits, with its estimated
+// probability between 0 and 1.
+type Suggestion struct {
+	Category   string
+	Confidence float64
+}
+
+// Classifier is a multinomial naive Bayes classifier over the words of a
+// description plus a token for the size of the amount.
+type Classifier struct {
+	numExamples      int
+	categoryCounts   map[string]int
+	tokenCounts      map[string]map[string]int
+	totalTokenCounts map[string]int
+	vocabulary       map[string]bool
+}
+
+func New() *Classifier {
+	return &Classifier{
+		categoryCounts:   map[string]int{},
+		tokenCounts:      map[string]map[string]int{},
+		totalTokenCounts: map[string]int{},
+		vocabulary:       map[string]bool{},
+	}
+}
+
+func Train(examples []Example) *Classifier {
+	c := New()
+	for _, e := range examples {
+		c.Learn(e.Description, e.Amount, e.Category)
+	}
+	return c
+}
+
+func (c *Classifier) NumExamples() int {
+	return c.numExamples
+}
+
+// Learn adds one categorized transaction to what the classifier knows.
+func (c *Classifier) Learn(description string, amount money.Amount, category string) {
+	if category == "" {
+		return
+	}
+	c.numExamples++
+	c.categoryCounts[category]++
+	if c.tokenCounts[category] == nil {
+		c.tokenCounts[category] = map[string]int{}
+	}
+	for _, token := range tokens(description, amount) {
+		c.tokenCounts[category][token]++
+		c.totalTokenCounts[category]++
+		c.vocabulary[token] = true
+	}
+}
+
+// Suggest returns up to n categories, most likely first. It returns nothing
+// until the classifier has seen an example.
+func (c *Classifier) Suggest(description string, amount money.Amount, n int) []Suggestion {
+	if c.numExamples == 0 || n <= 0 {
+		return nil
+	}
+	ts := tokens(description, amount)
+	vocabularySize := float64(len(c.vocabulary))
+
+	logProbs := make(map[string]float64, len(c.categoryCounts))
+	maxLogProb := math.Inf(-1)
+	for category, count := range c.categoryCounts {
+		logProb := math.Log(float64(count) / float64(c.numExamples))
+		denominator := float64(c.totalTokenCounts[category]) + vocabularySize
+		for _, token := range ts {
+			// Laplace smoothing keeps unseen words from ruling a category out.
+			logProb += math.Log((float64(c.tokenCounts[category][token]) + 1) / denominator)
+		}
+		logProbs[category] = logProb
+		maxLogProb = math.Max(maxLogProb, logProb)
+	}
+
+	// Normalize in log space first so tiny probabilities do not underflow.
+	var total float64
+	suggestions := make([]Suggestion, 0, len(logProbs))
+	for category, logProb := range logProbs {
+		p := math.Exp(logProb - maxLogProb)
+		total += p
+		suggestions = append(suggestions, Suggestion{Category: category, Confidence: p})
+	}
+	for i := range suggestions {
+		suggestions[i].Confidence /= total
+	}
+	sort.Slice(suggestions, func(x, y int) bool {
+		if suggestions[x].Confidence != suggestions[y].Confidence {
+			return suggestions[x].Confidence > suggestions[y].Confidence
+		}
+		return suggestions[x].Category < suggestions[y].Category
+	})
+	if len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
+
+// amountBuckets are the upper bounds, in dollars, of the amount tokens.
+var amountBuckets = []int64{10, 25, 50, 100, 250, 1000}
+
+// tokens splits description into lowercase words of at least two letters
+// and adds a token for the amount's bucket.
+func tokens(description string, amount money.Amount) []string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	ts := make([]string, 0, len(words)+1)
+	for _, word := range words {
+		if len(word) >= 2 {
+			ts = append(ts, word)
+		}
+	}
+	return append(ts, amountToken(amount))
+}
+
+func amountToken(amount money.Amount) string {
+	if amount < 0 {
+		return "amount:negative"
+	}
+	for _, bound := range amountBuckets {
+		if amount.Cents() < bound*100 {
+			return "amount:<" + money.FromCents(bound*100).String()
+		}
+	}
+	return "amount:large"
+}
@@ -0,0 +1,124 @@
+package classify
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+var testExamples = []Example{
+	{Description: "WEGMANS CHAPEL HILL", Amount: 8523, Category: "Groceries"},
+	{Description: "WEGMANS DURHAM", Amount: 6410, Category: "Groceries"},
+	{Description: "HARRIS TEETER", Amount: 4312, Category: "Groceries"},
+	{Description: "NEOMONDE RALEIGH", Amount: 1450, Category: "Food Out"},
+	{Description: "MILK BAR", Amount: 650, Category: "Food Out"},
+	{Description: "SHELL OIL", Amount: 4100, Category: "Gas"},
+}
+
+func TestSuggest(t *testing.T) {
+	c := Train(testExamples)
+	if c.NumExamples() != len(testExamples) {
+		t.Errorf("expected %d examples, got %d", len(testExamples), c.NumExamples())
+	}
+	tests := []struct {
+		description string
+		amount      money.Amount
+		expected    string
+	}{
+		{"WEGMANS CARY", 7000, "Groceries"},
+		{"TST* NEOMONDE", 1200, "Food Out"},
+		{"SHELL OIL 57444", 3800, "Gas"},
+		{"MILK BAR", 500, "Food Out"},
+	}
+	for _, test := range tests {
+		suggestions := c.Suggest(test.description, test.amount, 3)
+		if len(suggestions) != 3 {
+			t.Errorf("%s: expected 3 suggestions, got %d", test.description, len(suggestions))
+			continue
+		}
+		if suggestions[0].Category != test.expected {
+			t.Errorf("%s: expected %s first, got %+v", test.description, test.expected, suggestions)
+		}
+		var total float64
+		for i, s := range suggestions {
+			total += s.Confidence
+			if i > 0 && s.Confidence > suggestions[i-1].Confidence {
+				t.Errorf("%s: suggestions are not sorted by confidence: %+v", test.description, suggestions)
+			}
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s: expected the confidences of all categories to add up to 1, got %v", test.description, total)
+		}
+	}
+}
+
+func TestSuggestWithoutExamples(t *testing.T) {
+	c := New()
+	if got := c.Suggest("WEGMANS", 1000, 3); got != nil {
+		t.Errorf("expected no suggestions before learning, got %+v", got)
+	}
+	c.Learn("WEGMANS", 1000, "")
+	if c.NumExamples() != 0 {
+		t.Errorf("expected an uncategorized example to be ignored")
+	}
+	c.Learn("WEGMANS", 1000, "Groceries")
+	if got := c.Suggest("WEGMANS", 1000, 0); got != nil {
+		t.Errorf("expected no suggestions when asking for none, got %+v", got)
+	}
+	if got := c.Suggest("ANYTHING", 1000, 3); len(got) != 1 || got[0].Confidence != 1 {
+		t.Errorf("expected the only category with full confidence, got %+v", got)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		description string
+		amount      money.Amount
+		expected    string
+	}{
+		{"TST* THE RALEIGH BEER G", 1200, "tst the raleigh beer amount:<$25.00"},
+		{"CIRCLE K # 23101", 4100, "circle amount:<$50.00"},
+		{"REFUND", -500, "refund amount:negative"},
+		{"RENT", 150000, "rent amount:large"},
+		{"", 999, "amount:<$10.00"},
+	}
+	for _, test := range tests {
+		if got := strings.Join(tokens(test.description, test.amount), " "); got != test.expected {
+			t.Errorf("tokens(%q, %s): expected %q, got %q", test.description, test.amount, test.expected, got)
+		}
+	}
+}
+
+func TestExamplesFromLedger(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "ledger.csv")
+	contents := `Date,Category,Description,Amount
+01/05/2024,Groceries,WEGMANS,$85.23
+01/06/2024,,UNCATEGORIZED,$10.00
+01/07/2024,Food Out,NEOMONDE,not a number
+01/08/2024, Gas ,SHELL OIL,41.00
+short,row
+`
+	if err := os.WriteFile(fileName, []byte(contents), 0600); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+	examples, err := ExamplesFromLedger(fileName)
+	if err != nil {
+		t.Fatalf("ExamplesFromLedger returned error: %v", err)
+	}
+	expected := []Example{
+		{Description: "WEGMANS", Amount: 8523, Category: "Groceries"},
+		{Description: "SHELL OIL", Amount: 4100, Category: "Gas"},
+	}
+	if len(examples) != len(expected) {
+		t.Fatalf("expected %d examples, got %+v", len(expected), examples)
+	}
+	for i, e := range expected {
+		if examples[i] != e {
+			t.Errorf("example %d: expected %+v, got %+v", i, e, examples[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package classify
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// ExamplesFromLedger reads examples from a local CSV ledger laid out like the
+// spreadsheet: a header row, then Date, Category, Description and Amount
+// columns. Rows without a category or with an unreadable amount are skipped.
+func ExamplesFromLedger(fileName string) ([]Example, error) {
+	ledgerFile, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer ledgerFile.Close()
+
+	csvReader := csv.NewReader(ledgerFile)
+	csvReader.FieldsPerRecord = -1
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	examples := make([]Example, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if len(row) < 4 || strings.TrimSpace(row[1]) == "" {
+			continue
+		}
+		amount, err := money.Parse(row[3])
+		if err != nil {
+			continue
+		}
+		examples = append(examples, Example{
+			Description: row[2],
+			Amount:      amount,
+			Category:    strings.TrimSpace(row[1]),
+		})
+	}
+	return examples, nil
+}
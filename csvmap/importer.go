@@ -0,0 +1,217 @@
+package csvmap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// Importer parses CSV files laid out as its Mapping describes.
+type Importer struct {
+	Mapping Mapping
+}
+
+func (i Importer) Name() string {
+	return i.Mapping.Name
+}
+
+func (i Importer) Confidence(headerRow []string, sampleRows [][]string) float64 {
+	rows := append([][]string{headerRow}, sampleRows...)
+	if i.Mapping.SkipRows >= len(rows) {
+		return 0
+	}
+	columns, err := i.Mapping.columnIndexes(rows[i.Mapping.SkipRows])
+	if err != nil {
+		return 0
+	}
+	for _, row := range rows[i.Mapping.SkipRows+1:] {
+		if i.Mapping.isIgnored(row) {
+			continue
+		}
+		if _, err := i.Mapping.rowToTransaction(row, columns); err != nil {
+			return 0.5
+		}
+	}
+	// An exact header match is strong evidence. Finding a few commonly
+	// named columns such as Date and Amount is not, so a mapping without a
+	// header only just beats rows that fail to parse.
+	if len(i.Mapping.Header) > 0 {
+		return 1
+	}
+	return 0.6
+}
+
+func (i Importer) Import(csvContents [][]string) (standard.Statement, error) {
+	m := i.Mapping
+	if m.SkipRows >= len(csvContents) {
+		return nil, fmt.Errorf("file has %d rows but mapping skips %d before the header", len(csvContents), m.SkipRows)
+	}
+	columns, err := m.columnIndexes(csvContents[m.SkipRows])
+	if err != nil {
+		return nil, fmt.Errorf("failed to find columns in header row: %w", err)
+	}
+
+	firstDataRow := m.SkipRows + 1
+	s := make(standard.Statement, 0, len(csvContents)-firstDataRow)
+	for rowIndex := firstDataRow; rowIndex < len(csvContents); rowIndex++ {
+		row := csvContents[rowIndex]
+		if m.isIgnored(row) {
+			continue
+		}
+		t, err := m.rowToTransaction(row, columns)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert row %d to transaction: %w", rowIndex+1, err)
+		}
+		t.Provenance.Row = rowIndex + 1
+		s = append(s, t)
+	}
+	return s, nil
+}
+
+// columnIndexes maps the mapping's column names to their positions in
+// headerRow. Columns the mapping does not use are -1.
+type columnIndexes struct {
+	date, description, amount, debit, credit int
+	postedDate, category, itemType, memo     int
+}
+
+func (m Mapping) columnIndexes(headerRow []string) (c columnIndexes, err error) {
+	if len(m.Header) > 0 {
+		if len(headerRow) < len(m.Header) {
+			return c, fmt.Errorf("expected %d columns in header row but got %d", len(m.Header), len(headerRow))
+		}
+		for i, columnName := range m.Header {
+			if strings.TrimSpace(headerRow[i]) != columnName {
+				return c, fmt.Errorf("expected column %d to be named %s but is named %s", i+1, columnName, headerRow[i])
+			}
+		}
+	}
+
+	find := func(columnName string) (int, error) {
+		if columnName == "" {
+			return -1, nil
+		}
+		for i, cell := range headerRow {
+			if strings.EqualFold(strings.TrimSpace(cell), columnName) {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("no column named %s", columnName)
+	}
+	if c.date, err = find(m.DateColumn); err != nil {
+		return c, err
+	}
+	if c.description, err = find(m.DescriptionColumn); err != nil {
+		return c, err
+	}
+	if c.amount, err = find(m.AmountColumn); err != nil {
+		return c, err
+	}
+	if c.debit, err = find(m.DebitColumn); err != nil {
+		return c, err
+	}
+	if c.credit, err = find(m.CreditColumn); err != nil {
+		return c, err
+	}
+	if c.postedDate, err = find(m.PostedDateColumn); err != nil {
+		return c, err
+	}
+	if c.category, err = find(m.CategoryColumn); err != nil {
+		return c, err
+	}
+	if c.itemType, err = find(m.TypeColumn); err != nil {
+		return c, err
+	}
+	if c.memo, err = find(m.MemoColumn); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// isIgnored reports whether row is blank or a footer line.
+func (m Mapping) isIgnored(row []string) bool {
+	if strings.TrimSpace(strings.Join(row, "")) == "" {
+		return true
+	}
+	for _, prefix := range m.FooterPrefixes {
+		if strings.HasPrefix(strings.TrimSpace(row[0]), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m Mapping) rowToTransaction(row []string, c columnIndexes) (t standard.Transaction, err error) {
+	cell := func(index int) string {
+		if index < 0 || index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+
+	t.Date, err = date.Parse(cell(c.date), m.DateLayout)
+	if err != nil {
+		return t, fmt.Errorf("failed to parse date: %w", err)
+	}
+	t.Description = cell(c.description)
+	if c.postedDate >= 0 {
+		t.Provenance.PostedDate, err = date.Parse(cell(c.postedDate), m.DateLayout)
+		if err != nil {
+			return t, fmt.Errorf("failed to parse posted date: %w", err)
+		}
+	}
+	t.Provenance.SourceCategory = cell(c.category)
+	t.Provenance.ItemType = cell(c.itemType)
+	t.Provenance.Memo = cell(c.memo)
+
+	if c.amount >= 0 {
+		amount, err := money.Parse(cell(c.amount))
+		if err != nil {
+			return t, fmt.Errorf("failed to parse amount: %w", err)
+		}
+		if m.Sign == NegativeIsSpending {
+			amount = amount.Neg()
+		}
+		t.Amount = amount
+	} else {
+		debit, err := parseOptionalAmount(cell(c.debit))
+		if err != nil {
+			return t, fmt.Errorf("failed to parse debit: %w", err)
+		}
+		credit, err := parseOptionalAmount(cell(c.credit))
+		if err != nil {
+			return t, fmt.Errorf("failed to parse credit: %w", err)
+		}
+		t.Amount = debit.Abs().Sub(credit.Abs())
+	}
+	t.Fingerprint = m.fingerprint(t)
+	return t, nil
+}
+
+// fingerprint identifies t across overlapping exports of the same layout.
+// Two rows with the same fingerprint are the same transaction downloaded
+// twice.
+func (m Mapping) fingerprint(t standard.Transaction) string {
+	postedDate := ""
+	if !t.Provenance.PostedDate.IsZero() {
+		postedDate = t.Provenance.PostedDate.Format(date.ISOLayout)
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+		m.Name,
+		t.Date.Format(date.ISOLayout),
+		postedDate,
+		t.Description,
+		t.Amount.Decimal(),
+		t.Provenance.ItemType,
+	)
+}
+
+func parseOptionalAmount(s string) (money.Amount, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return money.Parse(s)
+}
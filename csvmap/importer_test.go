@@ -0,0 +1,172 @@
+package csvmap
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func loadMapping(t *testing.T, fileName string) Mapping {
+	t.Helper()
+	m, err := MappingFromFile(fileName)
+	if err != nil {
+		t.Fatalf("MappingFromFile(%s) returned error: %v", fileName, err)
+	}
+	return m
+}
+
+func TestChaseCreditMapping(t *testing.T) {
+	i := Importer{Mapping: loadMapping(t, "../mappings/chase-credit.json")}
+	csvContents := [][]string{
+		{"Transaction Date", "Post Date", "Description", "Category", "Type", "Amount", "Memo"},
+		{"01/05/2024", "01/07/2024", "COFFEE SHOP", "Food & Drink", "Sale", "-4.50", ""},
+		{"01/06/2024", "01/06/2024", "PAYMENT THANK YOU", "", "Payment", "100.00", "autopay"},
+	}
+	if got := i.Confidence(csvContents[0], csvContents[1:]); got != 1 {
+		t.Errorf("confidence: expected 1, got %v", got)
+	}
+	s, err := i.Import(csvContents)
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if len(s) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(s))
+	}
+
+	coffee := s[0]
+	if coffee.Date.Format(date.ISOLayout) != "2024-01-05" {
+		t.Errorf("date: expected 2024-01-05, got %s", coffee.Date)
+	}
+	if coffee.Amount != money.FromCents(450) {
+		t.Errorf("amount: expected 4.50, got %s", coffee.Amount)
+	}
+	if coffee.Provenance.PostedDate.Format(date.ISOLayout) != "2024-01-07" {
+		t.Errorf("posted date: expected 2024-01-07, got %s", coffee.Provenance.PostedDate)
+	}
+	if coffee.Provenance.SourceCategory != "Food & Drink" || coffee.Provenance.ItemType != "Sale" {
+		t.Errorf("provenance: expected Food & Drink and Sale, got %s and %s", coffee.Provenance.SourceCategory, coffee.Provenance.ItemType)
+	}
+	if coffee.Provenance.Row != 2 {
+		t.Errorf("row: expected 2, got %d", coffee.Provenance.Row)
+	}
+	if coffee.Fingerprint == "" || coffee.Fingerprint == s[1].Fingerprint {
+		t.Errorf("fingerprint: expected distinct non-empty fingerprints, got %q and %q", coffee.Fingerprint, s[1].Fingerprint)
+	}
+
+	payment := s[1]
+	if payment.Amount != money.FromCents(-10000) {
+		t.Errorf("amount: expected -100.00, got %s", payment.Amount)
+	}
+	if payment.Provenance.Memo != "autopay" {
+		t.Errorf("memo: expected autopay, got %s", payment.Provenance.Memo)
+	}
+}
+
+func TestDebitCreditMapping(t *testing.T) {
+	i := Importer{Mapping: loadMapping(t, "../mappings/capital-one.json")}
+	csvContents := [][]string{
+		{"Transaction Date", "Posted Date", "Card No.", "Description", "Category", "Debit", "Credit"},
+		{"2024-02-01", "2024-02-02", "1234", "GROCER", "Merchandise", "25.10", ""},
+		{"2024-02-03", "2024-02-03", "1234", "REFUND", "Merchandise", "", "5.00"},
+		{"Total", "", "", "", "", "25.10", "5.00"},
+	}
+	s, err := i.Import(csvContents)
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	expected := []money.Amount{money.FromCents(2510), money.FromCents(-500)}
+	if len(s) != len(expected) {
+		t.Fatalf("expected %d transactions, got %d", len(expected), len(s))
+	}
+	for j, amount := range expected {
+		if s[j].Amount != amount {
+			t.Errorf("transaction %d: expected amount %s, got %s", j, amount, s[j].Amount)
+		}
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	withHeader := Importer{Mapping: loadMapping(t, "../mappings/chase-credit.json")}
+	withoutHeader := Importer{Mapping: loadMapping(t, "../mappings/amex.json")}
+	tests := []struct {
+		name       string
+		importer   Importer
+		headerRow  []string
+		sampleRows [][]string
+		expected   float64
+	}{
+		{
+			name:       "exact header",
+			importer:   withHeader,
+			headerRow:  []string{"Transaction Date", "Post Date", "Description", "Category", "Type", "Amount", "Memo"},
+			sampleRows: [][]string{{"01/05/2024", "01/07/2024", "COFFEE", "Food", "Sale", "-4.50", ""}},
+			expected:   1,
+		},
+		{
+			name:       "different header",
+			importer:   withHeader,
+			headerRow:  []string{"Date", "Description", "Amount"},
+			sampleRows: [][]string{{"01/05/2024", "COFFEE", "4.50"}},
+			expected:   0,
+		},
+		{
+			name:       "columns found without a header",
+			importer:   withoutHeader,
+			headerRow:  []string{"Date", "Description", "Amount"},
+			sampleRows: [][]string{{"01/05/2024", "COFFEE", "4.50"}},
+			expected:   0.6,
+		},
+		{
+			name:       "rows do not parse",
+			importer:   withoutHeader,
+			headerRow:  []string{"Date", "Description", "Amount"},
+			sampleRows: [][]string{{"2024-01-05", "COFFEE", "4.50"}},
+			expected:   0.5,
+		},
+		{
+			name:       "missing column",
+			importer:   withoutHeader,
+			headerRow:  []string{"Date", "Description", "Debit"},
+			sampleRows: [][]string{{"01/05/2024", "COFFEE", "4.50"}},
+			expected:   0,
+		},
+	}
+	for _, test := range tests {
+		if got := test.importer.Confidence(test.headerRow, test.sampleRows); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Mapping{
+		Name:              "Bank",
+		DateColumn:        "Date",
+		DateLayout:        "01/02/2006",
+		DescriptionColumn: "Description",
+		AmountColumn:      "Amount",
+		Sign:              PositiveIsSpending,
+	}
+	tests := []struct {
+		name    string
+		modify  func(m *Mapping)
+		isValid bool
+	}{
+		{"valid", func(m *Mapping) {}, true},
+		{"no name", func(m *Mapping) { m.Name = "" }, false},
+		{"no date layout", func(m *Mapping) { m.DateLayout = "" }, false},
+		{"negative skip rows", func(m *Mapping) { m.SkipRows = -1 }, false},
+		{"amount and debit", func(m *Mapping) { m.DebitColumn = "Debit" }, false},
+		{"no amount", func(m *Mapping) { m.AmountColumn = "" }, false},
+		{"unknown sign", func(m *Mapping) { m.Sign = "backwards" }, false},
+		{"debit only", func(m *Mapping) { m.AmountColumn, m.Sign, m.DebitColumn = "", "", "Debit" }, true},
+	}
+	for _, test := range tests {
+		m := valid
+		test.modify(&m)
+		if err := m.validate(); (err == nil) != test.isValid {
+			t.Errorf("%s: expected valid %v, got error %v", test.name, test.isValid, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package csvmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// NegativeIsSpending is the sign convention where charges are negative,
+	// as in Chase credit card exports.
+	NegativeIsSpending = "negative_is_spending"
+	// PositiveIsSpending is the sign convention where charges are positive,
+	// as in Amex exports.
+	PositiveIsSpending = "positive_is_spending"
+)
+
+// Mapping describes the layout of a bank's CSV export. Columns are referred
+// to by their names in the header row.
+type Mapping struct {
+	Name string `json:"name"`
+	// Header, when set, is the exact header row the file must have.
+	Header []string `json:"header"`
+	// SkipRows is the number of rows before the header row.
+	SkipRows int `json:"skip_rows"`
+	// FooterPrefixes are the beginnings of first cells of rows to ignore,
+	// such as totals at the end of the file.
+	FooterPrefixes []string `json:"footer_prefixes"`
+
+	DateColumn string `json:"date_column"`
+	// DateLayout is in Go's time layout notation, e.g. 01/02/2006 for
+	// MM/DD/YYYY or 2006-01-02 for ISO 8601.
+	DateLayout        string `json:"date_layout"`
+	DescriptionColumn string `json:"description_column"`
+	// Either AmountColumn or at least one of DebitColumn and CreditColumn
+	// must be set.
+	AmountColumn string `json:"amount_column"`
+	DebitColumn  string `json:"debit_column"`
+	CreditColumn string `json:"credit_column"`
+	// Sign is NegativeIsSpending or PositiveIsSpending and applies to
+	// AmountColumn only. Debit and credit columns are read as magnitudes.
+	Sign string `json:"sign"`
+
+	// The remaining columns are optional and are kept in the transaction's
+	// provenance. PostedDateColumn is read with DateLayout.
+	PostedDateColumn string `json:"posted_date_column"`
+	CategoryColumn   string `json:"category_column"`
+	TypeColumn       string `json:"type_column"`
+	MemoColumn       string `json:"memo_column"`
+}
+
+func MappingFromFile(fileName string) (m Mapping, err error) {
+	mappingFile, err := os.Open(fileName)
+	if err != nil {
+		return m, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer mappingFile.Close()
+
+	mappingFileBytes, err := io.ReadAll(mappingFile)
+	if err != nil {
+		return m, fmt.Errorf("failed to read file: %w", err)
+	}
+	if err = json.Unmarshal(mappingFileBytes, &m); err != nil {
+		return m, fmt.Errorf("failed to unmarshal mapping: %w", err)
+	}
+	if err = m.validate(); err != nil {
+		return m, fmt.Errorf("invalid mapping: %w", err)
+	}
+	return m, nil
+}
+
+// MappingsFromDir loads every .json file in dirName as a mapping.
+func MappingsFromDir(dirName string) ([]Mapping, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dirName, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mapping files: %w", err)
+	}
+	mappings := make([]Mapping, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		m, err := MappingFromFile(fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load mapping from %s: %w", fileName, err)
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, nil
+}
+
+func (m Mapping) validate() error {
+	if m.Name == "" {
+		return errors.New("name is required")
+	}
+	if m.DateColumn == "" || m.DescriptionColumn == "" {
+		return errors.New("date_column and description_column are required")
+	}
+	if m.DateLayout == "" {
+		return errors.New("date_layout is required")
+	}
+	if m.SkipRows < 0 {
+		return fmt.Errorf("skip_rows is %d but must not be negative", m.SkipRows)
+	}
+	hasAmount := m.AmountColumn != ""
+	hasDebitCredit := m.DebitColumn != "" || m.CreditColumn != ""
+	if hasAmount == hasDebitCredit {
+		return errors.New("exactly one of amount_column or debit_column/credit_column must be set")
+	}
+	if hasAmount && m.Sign != NegativeIsSpending && m.Sign != PositiveIsSpending {
+		return fmt.Errorf("sign must be %s or %s but is '%s'", NegativeIsSpending, PositiveIsSpending, m.Sign)
+	}
+	return nil
+}
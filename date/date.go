@@ -0,0 +1,152 @@
+package date
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts for the date formats found in statements, in Go's time layout
+// notation.
+const (
+	USLayout     = "01/02/2006"
+	ISOLayout    = "2006-01-02"
+	DottedLayout = "02.01.2006"
+	OFXLayout    = "20060102"
+)
+
+// InputLayouts are the layouts tried, in order, when parsing dates the user
+// types in.
+var InputLayouts = []string{USLayout, "1/2/2006", ISOLayout, DottedLayout}
+
+// DisplayLayout is the layout used by String, and so for printing and for the
+// values written to the spreadsheet.
+var DisplayLayout = USLayout
+
+// Date is a calendar date with no time of day or time zone.
+type Date struct {
+	t time.Time
+}
+
+// New returns the date for the given year, month and day, failing if the
+// date does not exist, e.g. February 31st.
+func New(year int, month time.Month, day int) (Date, error) {
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if t.Year() != year || t.Month() != month || t.Day() != day {
+		return Date{}, fmt.Errorf("%04d-%02d-%02d is not a valid date", year, int(month), day)
+	}
+	return Date{t: t}, nil
+}
+
+// FromTime returns the date of t in t's location.
+func FromTime(t time.Time) Date {
+	return Date{t: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func Today() Date {
+	return FromTime(time.Now())
+}
+
+// Parse parses s with the first of layouts that fits, or with InputLayouts
+// if no layouts are given. Calendar validation comes from time.Parse, which
+// rejects days that do not exist in the month.
+func Parse(s string, layouts ...string) (Date, error) {
+	if len(layouts) == 0 {
+		layouts = InputLayouts
+	}
+	s = strings.TrimSpace(s)
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return FromTime(t), nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(layouts) == 1 {
+		return Date{}, firstErr
+	}
+	return Date{}, fmt.Errorf("'%s' does not match any of the layouts %v: %w", s, layouts, firstErr)
+}
+
+func (d Date) Format(layout string) string {
+	return d.t.Format(layout)
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DisplayLayout)
+}
+
+func (d Date) IsZero() bool {
+	return d.t.IsZero()
+}
+
+func (d Date) Year() int {
+	return d.t.Year()
+}
+
+func (d Date) Month() time.Month {
+	return d.t.Month()
+}
+
+func (d Date) Day() int {
+	return d.t.Day()
+}
+
+func (d Date) Weekday() time.Weekday {
+	return d.t.Weekday()
+}
+
+func (d Date) Before(other Date) bool {
+	return d.t.Before(other.t)
+}
+
+func (d Date) After(other Date) bool {
+	return d.t.After(other.t)
+}
+
+func (d Date) Equal(other Date) bool {
+	return d.t.Equal(other.t)
+}
+
+func (d Date) AddDays(days int) Date {
+	return Date{t: d.t.AddDate(0, 0, days)}
+}
+
+// DaysUntil returns the number of days from d to other, negative if other
+// is earlier.
+func (d Date) DaysUntil(other Date) int {
+	return int(other.t.Sub(d.t).Hours() / 24)
+}
+
+// MarshalJSON writes d in ISO 8601 format.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(d.Format(ISOLayout))
+}
+
+// UnmarshalJSON accepts an ISO 8601 date or any of InputLayouts.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := Parse(s, append([]string{ISOLayout}, InputLayouts...)...)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
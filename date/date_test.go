@@ -0,0 +1,126 @@
+package date
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		layouts  []string
+		expected string
+		isValid  bool
+	}{
+		{"01/31/2024", nil, "2024-01-31", true},
+		{"1/5/2024", nil, "2024-01-05", true},
+		{"2024-01-31", nil, "2024-01-31", true},
+		{"31.01.2024", nil, "2024-01-31", true},
+		{" 02/29/2024 ", nil, "2024-02-29", true},
+		{"20240131", []string{OFXLayout}, "2024-01-31", true},
+		{"02/29/2023", nil, "", false},
+		{"02/30/2024", nil, "", false},
+		{"2024-01-31", []string{USLayout}, "", false},
+		{"", nil, "", false},
+		{"yesterday", nil, "", false},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input, test.layouts...)
+		if (err == nil) != test.isValid {
+			t.Errorf("'%s': expected valid %v, got error %v", test.input, test.isValid, err)
+			continue
+		}
+		if test.isValid && got.Format(ISOLayout) != test.expected {
+			t.Errorf("'%s': expected %s, got %s", test.input, test.expected, got.Format(ISOLayout))
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		year    int
+		month   time.Month
+		day     int
+		isValid bool
+	}{
+		{2024, time.February, 29, true},
+		{2023, time.February, 29, false},
+		{2024, time.February, 31, false},
+		{2024, time.April, 31, false},
+		{2024, time.December, 31, true},
+		{2024, time.January, 0, false},
+		{2024, 13, 1, false},
+	}
+	for _, test := range tests {
+		d, err := New(test.year, test.month, test.day)
+		if (err == nil) != test.isValid {
+			t.Errorf("%d-%d-%d: expected valid %v, got error %v", test.year, test.month, test.day, test.isValid, err)
+			continue
+		}
+		if test.isValid && (d.Year() != test.year || d.Month() != test.month || d.Day() != test.day) {
+			t.Errorf("%d-%d-%d: got %s", test.year, test.month, test.day, d.Format(ISOLayout))
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	d, err := New(2024, time.February, 28)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	next := d.AddDays(2)
+	if next.Format(ISOLayout) != "2024-03-01" {
+		t.Errorf("AddDays: expected 2024-03-01, got %s", next.Format(ISOLayout))
+	}
+	if got := d.DaysUntil(next); got != 2 {
+		t.Errorf("DaysUntil: expected 2, got %d", got)
+	}
+	if got := next.DaysUntil(d); got != -2 {
+		t.Errorf("DaysUntil: expected -2, got %d", got)
+	}
+	if !d.Before(next) || !next.After(d) || d.Equal(next) {
+		t.Errorf("expected %s to be before %s", d, next)
+	}
+}
+
+func TestString(t *testing.T) {
+	if got := (Date{}).String(); got != "" {
+		t.Errorf("zero date: expected empty string, got %s", got)
+	}
+	d, err := New(2024, time.January, 5)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if got := d.String(); got != "01/05/2024" {
+		t.Errorf("expected 01/05/2024, got %s", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	d, err := New(2024, time.January, 5)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if string(b) != `"2024-01-05"` {
+		t.Errorf("Marshal: expected \"2024-01-05\", got %s", b)
+	}
+	for _, input := range []string{`"2024-01-05"`, `"01/05/2024"`} {
+		var got Date
+		if err := json.Unmarshal([]byte(input), &got); err != nil {
+			t.Errorf("Unmarshal %s returned error: %v", input, err)
+			continue
+		}
+		if !got.Equal(d) {
+			t.Errorf("Unmarshal %s: expected %s, got %s", input, d, got)
+		}
+	}
+	var zero Date
+	if err := json.Unmarshal([]byte(`""`), &zero); err != nil || !zero.IsZero() {
+		t.Errorf("Unmarshal empty string: expected zero date, got %s and error %v", zero, err)
+	}
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// FileImporter parses statement files that are not CSV, such as OFX
+// downloads, so they get the whole file instead of a header row.
+type FileImporter interface {
+	Name() string
+	// Confidence reports how sure the importer is that it can parse a file
+	// with the given name and contents, from 0 (cannot) to 1 (certain).
+	Confidence(fileName string, contents []byte) float64
+	Import(contents []byte) (standard.Statement, error)
+}
+
+var fileRegistry []FileImporter
+
+func RegisterFile(f FileImporter) {
+	fileRegistry = append(fileRegistry, f)
+}
+
+// ImportFile reads fileName and imports it with the most confident
+// FileImporter, falling back to parsing it as CSV for the CSV importers.
+// Every transaction's provenance records fileName as its source file.
+func ImportFile(fileName string) (s standard.Statement, importerName string, err error) {
+	s, importerName, err = importFile(fileName)
+	for i := range s {
+		s[i].Provenance.SourceFile = fileName
+	}
+	return s, importerName, err
+}
+
+func importFile(fileName string) (s standard.Statement, importerName string, err error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var best FileImporter
+	var bestConfidence float64
+	for _, f := range fileRegistry {
+		confidence := f.Confidence(fileName, contents)
+		if confidence > bestConfidence {
+			best, bestConfidence = f, confidence
+		}
+	}
+	if best != nil {
+		s, err = best.Import(contents)
+		if err != nil {
+			return nil, best.Name(), fmt.Errorf("failed to import with %s importer: %w", best.Name(), err)
+		}
+		return s, best.Name(), nil
+	}
+
+	csvContents, err := parseCsv(contents)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse file as csv: %w", err)
+	}
+	return Import(csvContents)
+}
+
+func parseCsv(contents []byte) (csvContents [][]string, err error) {
+	csvReader := csv.NewReader(bytes.NewReader(contents))
+	// some exports, e.g. Chase checking, end data rows with an extra empty field
+	csvReader.FieldsPerRecord = -1
+	csvContents, err = csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+	if len(csvContents) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	return csvContents, nil
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// numSampleRows is how many rows after the header are handed to each
+// importer when it is asked how confident it is.
+const numSampleRows = 5
+
+type Importer interface {
+	// Name identifies the importer in logs and error messages.
+	Name() string
+	// Confidence reports how sure the importer is that it can parse a file
+	// with the given header row and sample rows, from 0 (cannot) to 1 (certain).
+	Confidence(headerRow []string, sampleRows [][]string) float64
+	// Import converts the full contents of a CSV file, header row included,
+	// into a standard statement.
+	Import(csvContents [][]string) (standard.Statement, error)
+}
+
+var registry []Importer
+
+func Register(i Importer) {
+	registry = append(registry, i)
+}
+
+func Registered() []Importer {
+	return registry
+}
+
+// Detect returns the registered importer that is most confident it can parse
+// csvContents. Ties go to the importer that was registered first.
+func Detect(csvContents [][]string) (Importer, error) {
+	if len(csvContents) == 0 {
+		return nil, errors.New("csv contents are empty")
+	}
+	headerRow := csvContents[0]
+	sampleRows := csvContents[1:]
+	if len(sampleRows) > numSampleRows {
+		sampleRows = sampleRows[:numSampleRows]
+	}
+
+	var best Importer
+	var bestConfidence float64
+	for _, i := range registry {
+		confidence := i.Confidence(headerRow, sampleRows)
+		if confidence > bestConfidence {
+			best, bestConfidence = i, confidence
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("none of the %d registered importers recognize header row %v", len(registry), headerRow)
+	}
+	return best, nil
+}
+
+func Import(csvContents [][]string) (s standard.Statement, importerName string, err error) {
+	i, err := Detect(csvContents)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to detect statement format: %w", err)
+	}
+	s, err = i.Import(csvContents)
+	if err != nil {
+		return nil, i.Name(), fmt.Errorf("failed to import with %s importer: %w", i.Name(), err)
+	}
+	return s, i.Name(), nil
+}
@@ -0,0 +1,105 @@
+package keywords
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold is the similarity a keyword needs before FuzzyMatch
+// offers it. It lets a typo or a few truncated letters through but not a
+// different word of the same length.
+const DefaultThreshold = 0.85
+
+// FuzzyMatch is a keyword that nearly appears in a description.
+type FuzzyMatch struct {
+	Keyword    string
+	Category   string
+	Similarity float64
+}
+
+// FuzzyMatch finds the keyword most similar to description, for use when no
+// keyword appears in it exactly. Each word of a keyword is compared by edit
+// distance with the closest word of description, and the keyword's
+// similarity is the average over its words weighted by their length, so the
+// keyword "raleigh beer gar" is 86% similar to the description "TST* THE
+// RALEIGH BEER G". It reports false when no keyword reaches threshold.
+func (kwMap Map) FuzzyMatch(description string, threshold float64) (FuzzyMatch, bool) {
+	descriptionWords := words(description)
+	var best FuzzyMatch
+	for keyword, category := range kwMap {
+		similarity := tokenSetSimilarity(words(keyword), descriptionWords)
+		if similarity < threshold || similarity < best.Similarity {
+			continue
+		}
+		if similarity == best.Similarity && keyword > best.Keyword {
+			continue
+		}
+		best = FuzzyMatch{Keyword: keyword, Category: category, Similarity: similarity}
+	}
+	return best, best.Keyword != ""
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func tokenSetSimilarity(keywordWords, descriptionWords []string) float64 {
+	if len(keywordWords) == 0 || len(descriptionWords) == 0 {
+		return 0
+	}
+	var weighted, total float64
+	for _, kw := range keywordWords {
+		bestWord := 0.0
+		for _, dw := range descriptionWords {
+			if similarity := wordSimilarity(kw, dw); similarity > bestWord {
+				bestWord = similarity
+			}
+		}
+		n := float64(len([]rune(kw)))
+		weighted += n * bestWord
+		total += n
+	}
+	return weighted / total
+}
+
+// wordSimilarity is 1 minus the edit distance between a and b as a fraction
+// of the longer word.
+func wordSimilarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := len(ar)
+	if len(br) > longest {
+		longest = len(br)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ar, br))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,56 @@
+package keywords
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	kwMap := Map{
+		"raleigh beer gar": "Food/Drinks Out",
+		"wegmans":          "Groceries/Toiletries",
+		"circle k":         "Gas",
+	}
+	tests := []struct {
+		description        string
+		threshold          float64
+		expectedKeyword    string
+		expectedSimilarity float64
+	}{
+		{"TST* THE RALEIGH BEER G", DefaultThreshold, "raleigh beer gar", 12.0 / 14},
+		{"WEGMNS CHAPEL HILL #140", DefaultThreshold, "wegmans", 6.0 / 7},
+		{"WEGMANS CHAPEL HILL #140", DefaultThreshold, "wegmans", 1},
+		{"CIRCLE K # 23101", DefaultThreshold, "circle k", 1},
+		{"TST* THE RALEIGH BEER G", 0.9, "", 0},
+		{"HARRIS TEETER 0120", DefaultThreshold, "", 0},
+		{"", DefaultThreshold, "", 0},
+	}
+	for _, test := range tests {
+		match, ok := kwMap.FuzzyMatch(test.description, test.threshold)
+		if ok != (test.expectedKeyword != "") || match.Keyword != test.expectedKeyword {
+			t.Errorf("FuzzyMatch(%q, %v): expected keyword %q, got %q, %v", test.description, test.threshold, test.expectedKeyword, match.Keyword, ok)
+			continue
+		}
+		if ok && (math.Abs(match.Similarity-test.expectedSimilarity) > 1e-9 || match.Category != kwMap[match.Keyword]) {
+			t.Errorf("FuzzyMatch(%q): expected similarity %v and category %s, got %+v", test.description, test.expectedSimilarity, kwMap[match.Keyword], match)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"wegmans", "wegmns", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, test := range tests {
+		if got := levenshtein([]rune(test.a), []rune(test.b)); got != test.expected {
+			t.Errorf("levenshtein(%q, %q): expected %d, got %d", test.a, test.b, test.expected, got)
+		}
+	}
+}
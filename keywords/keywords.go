@@ -6,34 +6,18 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
 )
 
+// Map maps each keyword to the name of its category.
 type Map map[string]string
 
-type keywords struct {
-	Rent                []string `json:"rent"`
-	Utilities           []string `json:"utilities"`
-	GroceriesToiletries []string `json:"groceries_toiletries"`
-	FoodDrinksOut       []string `json:"food_drinks_out"`
-	Gas                 []string `json:"gas"`
-	OtherNeed           []string `json:"other_need"`
-	OtherWant           []string `json:"other_want"`
-	GiftGiving          []string `json:"gift_giving"`
-	Donations           []string `json:"donations"`
-	Skip                []string `json:"skip"`
-}
-
-func (kwMap Map) Search(description string) (category string, foundMatch bool) {
-	for word, associatedCategory := range kwMap {
-		if strings.Contains(strings.ToLower(description), strings.ToLower(word)) {
-			return associatedCategory, true
-		}
-	}
-	return "", false
-}
+// keywords maps category keys to the keywords of that category, as laid out
+// in keywords.json.
+type keywords map[string][]string
 
-func MapFromFile(fileName string) (kwMap Map, err error) {
+func MapFromFile(fileName string, categories category.List) (kwMap Map, err error) {
 	keywordsFile, err := os.Open(fileName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
@@ -49,31 +33,23 @@ func MapFromFile(fileName string) (kwMap Map, err error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal keywords: %w", err)
 	}
-	kwMap, err = buildKeywordMap(kw)
+	kwMap, err = buildKeywordMap(kw, categories)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build keyword map: %w", err)
 	}
 	return kwMap, err
 }
 
-func buildKeywordMap(kw keywords) (Map, error) {
-	categories := map[string][]string{
-		"Rent":                 kw.Rent,
-		"Utilities":            kw.Utilities,
-		"Groceries/Toiletries": kw.GroceriesToiletries,
-		"Food/Drinks Out":      kw.FoodDrinksOut,
-		"Gas":                  kw.Gas,
-		"Other (Need)":         kw.OtherNeed,
-		"Other (Want)":         kw.OtherWant,
-		"Gift Giving":          kw.GiftGiving,
-		"Donations":            kw.Donations,
-		"skip":                 kw.Skip,
-	}
+func buildKeywordMap(kw keywords, categories category.List) (Map, error) {
 	kwMap := Map{}
-	for category, categoryWords := range categories {
-		err := kwMap.add(category, categoryWords)
+	for categoryKey, categoryWords := range kw {
+		c, ok := categories.ByKey(categoryKey)
+		if !ok {
+			return nil, fmt.Errorf("keywords file refers to unknown category key %s", categoryKey)
+		}
+		err := kwMap.add(c.Name, categoryWords)
 		if err != nil {
-			return nil, fmt.Errorf("failed to add keywords for %s to keyword map: %v", category, err)
+			return nil, fmt.Errorf("failed to add keywords for %s to keyword map: %v", c.Name, err)
 		}
 	}
 	return kwMap, nil
@@ -0,0 +1,126 @@
+package keywords
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/merchant"
+)
+
+// Suggest proposes a keyword for description: the lowercase leading words of
+// its merchant name as merchant.Clean leaves it with cities, ending at a '#'
+// or '*' and before any later word with a digit, such as a reference number.
+func Suggest(description string, cities []string) string {
+	var words []string
+	for i, word := range strings.Fields(strings.ToLower(merchant.Clean(description, cities))) {
+		if end := strings.IndexAny(word, "#*"); end >= 0 {
+			if word = word[:end]; word != "" {
+				words = append(words, word)
+			}
+			break
+		}
+		if i > 0 && strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			break
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// AddToFile adds word to the keywords of the category named categoryName in
+// fileName. The file keeps its layout: categories in their configured order,
+// existing words in their original order and four-space indentation. Adding
+// a word that is already in the file, in any category or case, is an error.
+func AddToFile(fileName string, categories category.List, categoryName, word string) error {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return errors.New("keyword is empty")
+	}
+	c, ok := categories.ByName(categoryName)
+	if !ok {
+		return fmt.Errorf("unknown category %s", categoryName)
+	}
+
+	keywordsFileBytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	var kw keywords
+	if err = json.Unmarshal(keywordsFileBytes, &kw); err != nil {
+		return fmt.Errorf("failed to unmarshal keywords: %w", err)
+	}
+	for categoryKey, words := range kw {
+		for _, existing := range words {
+			if strings.EqualFold(existing, word) {
+				return fmt.Errorf("keyword '%s' already exists under %s", existing, categoryKey)
+			}
+		}
+	}
+	kw[c.Key] = append(kw[c.Key], word)
+
+	out, err := marshalKeywords(kw, categories)
+	if err != nil {
+		return fmt.Errorf("failed to marshal keywords: %w", err)
+	}
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if err = os.WriteFile(fileName, out, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// marshalKeywords lays out kw like keywords.json: one category key per line
+// in category order, with keys unknown to categories at the end.
+func marshalKeywords(kw keywords, categories category.List) ([]byte, error) {
+	keys := make([]string, 0, len(kw))
+	for key := range kw {
+		keys = append(keys, key)
+	}
+	orderOf := func(key string) int {
+		for i, c := range categories {
+			if c.Key == key {
+				return i
+			}
+		}
+		return len(categories)
+	}
+	sort.SliceStable(keys, func(x, y int) bool {
+		if orderOf(keys[x]) != orderOf(keys[y]) {
+			return orderOf(keys[x]) < orderOf(keys[y])
+		}
+		return keys[x] < keys[y]
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, key := range keys {
+		words := kw[key]
+		if words == nil {
+			words = []string{}
+		}
+		var value bytes.Buffer
+		encoder := json.NewEncoder(&value)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("    ", "    ")
+		if err := encoder.Encode(words); err != nil {
+			return nil, fmt.Errorf("failed to encode keywords for %s: %w", key, err)
+		}
+		fmt.Fprintf(&buf, "    %q: %s", key, bytes.TrimSpace(value.Bytes()))
+		if i < len(keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
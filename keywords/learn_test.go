@@ -0,0 +1,102 @@
+package keywords
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+)
+
+func TestSuggest(t *testing.T) {
+	cities := []string{"Chapel Hill", "Durham"}
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"TST* Neomonde - Durham", "neomonde"},
+		{"WEGMANS CHAPEL HILL #140", "wegmans"},
+		{"AMZN Mktp US*2K4L93", "amzn mktp us"},
+		{"DOORDASH*CHIPOTLE", "doordash"},
+		{"UBER TRIP 8005928996 CA", "uber trip"},
+		{"7-ELEVEN 35210", "7-eleven"},
+		{"SHELL OIL 57444", "shell oil"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := Suggest(test.description, cities); got != test.expected {
+			t.Errorf("Suggest(%q): expected %q, got %q", test.description, test.expected, got)
+		}
+	}
+}
+
+var testCategories = category.List{
+	{Name: "Groceries", Key: "groceries", Order: 1},
+	{Name: "Food Out", Key: "food_out", Order: 2},
+	{Name: "Gas", Key: "gas", Order: 3},
+}
+
+func writeKeywordsFile(t *testing.T, contents string) string {
+	t.Helper()
+	fileName := filepath.Join(t.TempDir(), "keywords.json")
+	if err := os.WriteFile(fileName, []byte(contents), 0600); err != nil {
+		t.Fatalf("failed to write keywords file: %v", err)
+	}
+	return fileName
+}
+
+func TestAddToFile(t *testing.T) {
+	fileName := writeKeywordsFile(t, `{
+    "food_out": ["neomonde"],
+    "groceries": ["wegmans", "harris teeter"],
+    "old_key": ["gone"]
+}`)
+	if err := AddToFile(fileName, testCategories, "Food Out", " milk bar "); err != nil {
+		t.Fatalf("AddToFile returned error: %v", err)
+	}
+	got, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to read keywords file: %v", err)
+	}
+	expected := `{
+    "groceries": [
+        "wegmans",
+        "harris teeter"
+    ],
+    "food_out": [
+        "neomonde",
+        "milk bar"
+    ],
+    "old_key": [
+        "gone"
+    ]
+}`
+	if string(got) != expected {
+		t.Errorf("expected file:\n%s\ngot:\n%s", expected, got)
+	}
+	info, err := os.Stat(fileName)
+	if err != nil {
+		t.Fatalf("failed to stat keywords file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600 to be kept, got %v", info.Mode().Perm())
+	}
+}
+
+func TestAddToFileErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		categoryName string
+		word         string
+	}{
+		{"empty word", "Gas", "  "},
+		{"unknown category", "Travel", "delta"},
+		{"existing word in another case", "Gas", "WEGMANS"},
+	}
+	for _, test := range tests {
+		fileName := writeKeywordsFile(t, `{"groceries": ["wegmans"]}`)
+		if err := AddToFile(fileName, testCategories, test.categoryName, test.word); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
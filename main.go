@@ -2,15 +2,28 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"encoding/json"
-	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
+	"github.com/Jack-Timothy/sheets-client/account"
+	"github.com/Jack-Timothy/sheets-client/category"
 	"github.com/Jack-Timothy/sheets-client/chase"
+	"github.com/Jack-Timothy/sheets-client/classify"
+	"github.com/Jack-Timothy/sheets-client/csvmap"
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/importer"
+	"github.com/Jack-Timothy/sheets-client/keywords"
+	"github.com/Jack-Timothy/sheets-client/merchant"
+	"github.com/Jack-Timothy/sheets-client/ofx"
+	"github.com/Jack-Timothy/sheets-client/qif"
+	"github.com/Jack-Timothy/sheets-client/rules"
+	"github.com/Jack-Timothy/sheets-client/sheet"
+	"github.com/Jack-Timothy/sheets-client/standard"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
@@ -72,27 +85,18 @@ func saveToken(path string, token *oauth2.Token) {
 	json.NewEncoder(f).Encode(token)
 }
 
-func main() {
-	csvFileName := "sample-statement.csv"
-	csvContents, err := getCsvContents(csvFileName)
-	if err != nil {
-		log.Fatalf("Error getting contents of %s: %v", csvFileName, err)
-	}
-
-	chaseStatement, err := chase.CsvContentsToStatement(csvContents)
-	if err != nil {
-		log.Fatalf("Error converting csv contents to Chase statement: %v", err)
-	}
+// manualSource is the source of transactions the user adds by hand.
+const manualSource = "manual"
 
-	standardStatement, err := chaseStatement.Standardize()
-	if err != nil {
-		log.Fatalf("Error standardizing Chase statement: %v", err)
-	}
-
-	if err = standardStatement.AcceptUserEdits(); err != nil {
-		log.Fatalf("Error during user edits of statement: %v", err)
-	}
+// Special values of the -history flag.
+const (
+	historyFromSheet = "sheet"
+	noHistory        = "none"
+)
 
+// newSheetsService authorizes with the credentials in credentials.json and
+// returns a Sheets service.
+func newSheetsService() *sheets.Service {
 	b, err := os.ReadFile("credentials.json")
 	if err != nil {
 		log.Fatalf("Unable to read client secret file: %v", err)
@@ -110,61 +114,299 @@ func main() {
 	if err != nil {
 		log.Fatalf("Unable to retrieve Sheets client: %v", err)
 	}
+	return srv
+}
+
+// trainClassifier trains a classifier on the rows already in the sheet or on
+// a local ledger file, depending on history. It returns nil for noHistory.
+func trainClassifier(history string, sheetRows standard.Statement) (*classify.Classifier, error) {
+	if history == noHistory {
+		return nil, nil
+	}
+	examples, err := historyExamples(history, sheetRows)
+	if err != nil {
+		return nil, err
+	}
+	classifier := classify.Train(examples)
+	log.Printf("Trained category suggestions on %d transactions.\n", classifier.NumExamples())
+	return classifier, nil
+}
+
+// historyExamples returns the already categorized transactions named by
+// history: sheetRows, which were read from the sheet, or the rows of a local
+// ledger file.
+func historyExamples(history string, sheetRows standard.Statement) ([]classify.Example, error) {
+	var examples []classify.Example
+	switch history {
+	case noHistory:
+		return nil, nil
+	case historyFromSheet:
+		for _, t := range sheetRows {
+			examples = append(examples, classify.Example{
+				Description: t.Description,
+				Amount:      t.Amount,
+				Category:    t.Category,
+			})
+		}
+	default:
+		var err error
+		if examples, err = classify.ExamplesFromLedger(history); err != nil {
+			return nil, fmt.Errorf("failed to read ledger %s: %w", history, err)
+		}
+	}
+	return examples, nil
+}
 
-	// Prints the data in a test spreadsheet:
-	// https://docs.google.com/spreadsheets/d/15KWFkIY-RW81leDLXqahARB0gtSnWAIGDg-lkx2g04Q/edit
-	spreadsheetId := "1dnKqyF20h90PT1ualeQHZJkJVH1LpnhZMRNH4-kwxws"
-	readRange := "Sheet1!A:D"
-	resp, err := srv.Spreadsheets.Values.Get(spreadsheetId, readRange).Do()
+// registerImporters registers the built-in importers and one per mapping
+// file in mappingsDirName. Importers that know transfers file them under the
+// first transfer category of categories.
+func registerImporters(mappingsDirName string, categories category.List) error {
+	importer.Register(chase.CheckingImporter{})
+	mappings, err := csvmap.MappingsFromDir(mappingsDirName)
 	if err != nil {
-		log.Fatalf("Unable to retrieve data from sheet: %v", err)
+		return fmt.Errorf("failed to load CSV mappings: %w", err)
+	}
+	for _, m := range mappings {
+		importer.Register(csvmap.Importer{Mapping: m})
 	}
+	importer.RegisterFile(ofx.Importer{})
+	transferCategory, _ := categories.FirstOfType(category.Transfer)
+	importer.RegisterFile(qif.Importer{TransferCategory: transferCategory.Name})
+	return nil
+}
 
-	if len(resp.Values) == 0 {
-		fmt.Println("No data found.")
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "rules" {
+		rulesCommand(os.Args[2:])
 		return
 	}
 
-	numRows := len(resp.Values) - 1
-	fmt.Printf("Found %d rows of data.\n", numRows)
-	for _, row := range resp.Values {
-		output := ""
-		for _, column := range row {
-			output += column.(string) + ", "
+	qifOutFileName := flag.String("qif-out", "", "also write the accepted statement to this QIF file")
+	qifType := flag.String("qif-type", "CCard", "QIF section type to use with -qif-out, e.g. Bank or CCard")
+	mappingsDirName := flag.String("mappings", "mappings", "directory of CSV column mapping files")
+	dateFormat := flag.String("date-format", date.DisplayLayout, "Go time layout for dates shown and written to the sheet")
+	fromDate := flag.String("from", "", "only keep transactions on or after this date")
+	toDate := flag.String("to", "", "only keep transactions on or before this date")
+	spreadsheetID := flag.String("spreadsheet", "1dnKqyF20h90PT1ualeQHZJkJVH1LpnhZMRNH4-kwxws", "ID of the spreadsheet to write to")
+	sheetName := flag.String("sheet", "Sheet1", "name of the sheet to write to")
+	rulesFileName := flag.String("rules", "rules.json", "JSON file of categorization rules checked along with keywords.json")
+	aliasesFileName := flag.String("aliases", "aliases.json", "JSON file of merchant name aliases")
+	keywordsFileName := flag.String("keywords", "keywords.json", "JSON file of keywords per category key")
+	categoriesFileName := flag.String("categories", "categories.json", "JSON file defining the categories")
+	fuzzyThreshold := flag.Float64("fuzzy-threshold", keywords.DefaultThreshold, "similarity between 0 and 1 a keyword needs to be offered for a description it does not match exactly; 0 turns fuzzy matching off")
+	refundWindow := flag.Int("refund-window", standard.DefaultRefundWindow, "days after a purchase to match refunds from the same merchant to it; 0 turns refund matching off")
+	transferDays := flag.Int("transfer-days", standard.DefaultTransferDays, "days apart the two sides of a transfer between accounts may be; 0 turns transfer matching off")
+	accountsFileName := flag.String("accounts", "accounts.json", "JSON file defining the accounts statements come from")
+	accountNames := flag.String("account", "", "comma-separated names of the only accounts to keep transactions from")
+	provenanceColumns := flag.String("provenance-columns", "", "comma-separated provenance fields to write as extra sheet columns, from "+strings.Join(standard.ProvenanceFields, ", "))
+	provenanceNote := flag.String("provenance-note", "", "comma-separated provenance fields to write as a note on each description cell")
+	history := flag.String("history", historyFromSheet, "categorized transactions to learn category suggestions from: 'sheet', a CSV ledger file, or 'none'")
+	flag.Parse()
+	date.DisplayLayout = *dateFormat
+	from, to, err := parseDateRange(*fromDate, *toDate)
+	if err != nil {
+		log.Fatalf("Error parsing date range: %v", err)
+	}
+	provenanceColumnFields := provenanceFieldsOf(*provenanceColumns)
+	provenanceNoteFields := provenanceFieldsOf(*provenanceNote)
+
+	categories, err := category.ListFromFile(*categoriesFileName)
+	if err != nil {
+		log.Fatalf("Error loading categories: %v", err)
+	}
+	if err = registerImporters(*mappingsDirName, categories); err != nil {
+		log.Fatalf("Error registering importers: %v", err)
+	}
+
+	accounts, err := account.ListFromFile(*accountsFileName)
+	if err != nil {
+		log.Fatalf("Error loading accounts: %v", err)
+	}
+
+	fileNames := flag.Args()
+	if len(fileNames) == 0 {
+		fileNames = []string{"sample-statement.csv"}
+	}
+	importedStatements := make([]standard.Statement, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		s, importerName, err := importer.ImportFile(fileName)
+		if err != nil {
+			log.Fatalf("Error importing %s: %v", fileName, err)
+		}
+		err = s.ResolveAccounts(func(sourceID string) (string, error) {
+			return accountOf(accounts, fileName, sourceID)
+		})
+		if err != nil {
+			log.Fatalf("Error finding the accounts of %s: %v", fileName, err)
 		}
-		output = output[:len(output)-2]
-		fmt.Println(output)
+		log.Printf("Imported %d transactions from %s with the %s importer into accounts '%s'.\n", len(s), fileName, importerName, strings.Join(s.AccountNames(), "', '"))
+		s.AssignImportIDs(importerName)
+		importedStatements = append(importedStatements, s)
+	}
+	importedStatement, numDropped := standard.Merge(importedStatements...)
+	if numDropped > 0 {
+		log.Printf("Dropped %d transactions that appeared in more than one file.\n", numDropped)
 	}
 
-	fmt.Println("Writing...")
-	rowNumToStartWrite := 2 + numRows
-	rowNumToEndWrite := rowNumToStartWrite + len(standardStatement)
-	writeRange := fmt.Sprintf("Sheet1!A%d:D%d", rowNumToStartWrite, rowNumToEndWrite)
-	newValues := &sheets.ValueRange{
-		MajorDimension: "ROWS",
-		Range:          writeRange,
-		Values:         standardStatement.GetRawData(),
+	normalizer, err := merchant.NormalizerFromFile(*aliasesFileName)
+	if err != nil {
+		log.Fatalf("Error loading merchant aliases: %v", err)
 	}
-	_, err = srv.Spreadsheets.Values.Update(spreadsheetId, writeRange, newValues).ValueInputOption("USER_ENTERED").Do()
+	importedStatement.NormalizeDescriptions(normalizer.Normalize)
+
+	if transferCategory, ok := categories.FirstOfType(category.Transfer); ok && *transferDays > 0 {
+		numPairs, unmatched := importedStatement.MarkTransfers(transferCategory.Name, *transferDays)
+		log.Printf("Marked %d pairs of transactions as transfers between accounts.\n", numPairs)
+		if len(unmatched) > 0 {
+			fmt.Println("These look like payments or transfers but have no matching transaction in another account:")
+			unmatched.Print(false)
+		}
+	}
+
+	ruleEngine, err := rules.NewEngineFromFiles(*rulesFileName, *keywordsFileName, categories)
+	if err != nil {
+		log.Fatalf("Error loading categorization rules: %v", err)
+	}
+	kwMap, err := keywords.MapFromFile(*keywordsFileName, categories)
+	if err != nil {
+		log.Fatalf("Error loading keywords: %v", err)
+	}
+	sheetClient := sheet.NewClient(newSheetsService(), *spreadsheetID, *sheetName, categories)
+	sheetClient.ProvenanceColumns = provenanceColumnFields
+	sheetClient.ProvenanceNote = provenanceNoteFields
+	var sheetRows standard.Statement
+	if *history == historyFromSheet {
+		if sheetRows, err = sheetClient.ReadStatement(); err != nil {
+			log.Fatalf("Error reading sheet history: %v", err)
+		}
+	}
+	classifier, err := trainClassifier(*history, sheetRows)
+	if err != nil {
+		log.Fatalf("Error training classifier: %v", err)
+	}
+	categorizer := &standard.Categorizer{
+		Categories:       categories,
+		Rules:            ruleEngine,
+		Classifier:       classifier,
+		Keywords:         kwMap,
+		FuzzyThreshold:   *fuzzyThreshold,
+		KeywordsFileName: *keywordsFileName,
+		Cities:           normalizer.Cities,
+		RefundWindow:     *refundWindow,
+		History:          sheetRows,
+	}
+	toCategorize := importedStatement.Between(from, to)
+	if *accountNames != "" {
+		var names []string
+		for _, name := range strings.Split(*accountNames, ",") {
+			name = strings.TrimSpace(name)
+			if _, ok := accounts.ByName(name); !ok {
+				log.Fatalf("Error filtering by account: unknown account %s", name)
+			}
+			names = append(names, name)
+		}
+		toCategorize = toCategorize.ForAccounts(names...)
+	}
+	standardStatement, err := categorizer.Categorize(toCategorize)
+	if err != nil {
+		log.Fatalf("Error categorizing statement: %v", err)
+	}
+
+	if err = standardStatement.AcceptUserEdits(categories); err != nil {
+		log.Fatalf("Error during user edits of statement: %v", err)
+	}
+
+	fmt.Println("Category totals, net of refunds:")
+	standardStatement.PrintCategoryTotals(categories)
+	fmt.Println("Account totals:")
+	standardStatement.PrintAccountTotals()
+
+	if *qifOutFileName != "" {
+		if err = writeQifFile(*qifOutFileName, standardStatement, *qifType); err != nil {
+			log.Fatalf("Error writing QIF file %s: %v", *qifOutFileName, err)
+		}
+	}
+
+	standardStatement.AssignImportIDs(manualSource)
+	fmt.Println("Writing...")
+	numAppended, numSkipped, err := sheetClient.Append(standardStatement)
 	if err != nil {
 		log.Fatalf("Unable to write data to sheet: %v", err)
 	}
+	fmt.Printf("Added %d transactions. Skipped %d transactions that were already in the sheet.\n", numAppended, numSkipped)
 }
 
-func getCsvContents(fileName string) (csvContents [][]string, err error) {
-	csvFile, err := os.Open(fileName)
+// accountOf finds the configured account of the transactions read from
+// fileName that the source gave the account ID sourceID, which may be empty:
+// by that ID, by the file's name or, failing both, by asking. It returns
+// sourceID if the account stays unknown, so transactions of different
+// accounts in one file are still told apart.
+func accountOf(accounts account.List, fileName, sourceID string) (string, error) {
+	if a, ok := accounts.ForOFXID(sourceID); ok {
+		return a.Name, nil
+	}
+	if a, ok := accounts.ForFile(fileName); ok {
+		return a.Name, nil
+	}
+	if len(accounts) == 0 {
+		return sourceID, nil
+	}
+	labels := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		labels = append(labels, a.Label())
+	}
+	source := fileName
+	if sourceID != "" {
+		source = fmt.Sprintf("account %s in %s", sourceID, fileName)
+	}
+	i, err := standard.GetAccountFromUser(source, labels)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return "", fmt.Errorf("failed to get account from user: %w", err)
+	}
+	if i < 0 {
+		return sourceID, nil
 	}
-	defer csvFile.Close()
+	return accounts[i].Name, nil
+}
 
-	csvReader := csv.NewReader(csvFile)
-	csvContents, err = csvReader.ReadAll()
+// provenanceFieldsOf splits a comma-separated list of provenance fields,
+// exiting if one is unknown.
+func provenanceFieldsOf(list string) []string {
+	if list == "" {
+		return nil
+	}
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		fields = append(fields, strings.TrimSpace(field))
+	}
+	if err := standard.ValidateProvenanceFields(fields); err != nil {
+		log.Fatalf("Error parsing provenance fields: %v", err)
+	}
+	return fields
+}
+
+func writeQifFile(fileName string, s standard.Statement, sectionType string) error {
+	f, err := os.Create(fileName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+	if err = qif.Write(f, s, sectionType); err != nil {
+		return fmt.Errorf("failed to write QIF: %w", err)
 	}
-	if len(csvContents) == 0 {
-		return nil, errors.New("file is empty")
+	return f.Close()
+}
+
+func parseDateRange(fromInput, toInput string) (from, to date.Date, err error) {
+	if fromInput != "" {
+		if from, err = date.Parse(fromInput); err != nil {
+			return from, to, fmt.Errorf("failed to parse from date: %w", err)
+		}
+	}
+	if toInput != "" {
+		if to, err = date.Parse(toInput); err != nil {
+			return from, to, fmt.Errorf("failed to parse to date: %w", err)
+		}
 	}
-	return csvContents, nil
+	return from, to, nil
 }
@@ -0,0 +1,150 @@
+package merchant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// processorPrefix matches payment processor markers such as "SQ *",
+	// "TST* " or "PAYPAL *" at the start of a description.
+	processorPrefix = regexp.MustCompile(`(?i)^(SQ|TST|PAYPAL|SP|PY|IN|DD|CKE|LS|BT|SQU)\s*\*\s*`)
+	// storeNumber matches store numbers like "#140" or "#1234567", which can
+	// be glued to the following word as in "BP#1234567CIRCLE K".
+	storeNumber = regexp.MustCompile(`#\s*\d+`)
+	// storeLocation matches a store number, which may end in a letter, that
+	// is followed by nothing but the store's location, as in
+	// "REI #85 DURHAM" or "BP#2300671S WILMST FAMIL".
+	storeLocation = regexp.MustCompile(`#\s*\d+[A-Za-z]?(\s.*)?$`)
+	// trailingSeparators matches what is left dangling once a location is
+	// gone, as in "NEOMONDE - ".
+	trailingSeparators = regexp.MustCompile(`[\s,-]+$`)
+	// trailingNumbers matches numeric tokens at the end, as in
+	// "HARRIS TEETER 0120".
+	trailingNumbers = regexp.MustCompile(`(\s+[\d-]+)+$`)
+	// locationSuffix matches a city and state that Chase pads away from the
+	// merchant with several spaces, or that follows a comma, e.g.
+	// "WEGMANS   CHAPEL HILL NC" or "NEOMONDE, RALEIGH, NC".
+	locationSuffix = regexp.MustCompile(`(?i)(\s{2,}|\s*,\s*)([a-z .'-]*?[\s,]+)?([a-z]{2})$`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+var stateCodes = map[string]bool{}
+
+func init() {
+	for _, code := range strings.Fields(`AL AK AZ AR CA CO CT DE DC FL GA HI ID IL IN IA KS KY LA ME MD MA MI MN MS
+		MO MT NE NV NH NJ NM NY NC ND OH OK OR PA RI SC SD TN TX UT VT VA WA WV WI WY PR`) {
+		stateCodes[code] = true
+	}
+}
+
+// Alias renames every merchant whose cleaned name contains Match, ignoring
+// case, to Name.
+type Alias struct {
+	Match string `json:"match"`
+	Name  string `json:"name"`
+}
+
+// Normalizer turns raw bank descriptions into clean merchant names. Cities
+// are the city names stripped from the end of descriptions that name no
+// state, as in "WEGMANS CHAPEL HILL".
+type Normalizer struct {
+	Aliases []Alias
+	Cities  []string
+}
+
+type aliasesFile struct {
+	Aliases []Alias  `json:"aliases"`
+	Cities  []string `json:"cities"`
+}
+
+func NormalizerFromFile(fileName string) (*Normalizer, error) {
+	aliasesFileHandle, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer aliasesFileHandle.Close()
+
+	aliasesFileBytes, err := io.ReadAll(aliasesFileHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var af aliasesFile
+	if err = json.Unmarshal(aliasesFileBytes, &af); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal aliases: %w", err)
+	}
+	for i, a := range af.Aliases {
+		if a.Match == "" || a.Name == "" {
+			return nil, fmt.Errorf("alias %d needs both match and name", i+1)
+		}
+	}
+	return &Normalizer{Aliases: af.Aliases, Cities: af.Cities}, nil
+}
+
+// Normalize removes processor prefixes, store numbers and locations from
+// description, then applies the first matching alias or title-cases what is
+// left. A description that would be left empty is returned as it was.
+func (n *Normalizer) Normalize(description string) string {
+	cleaned := Clean(description, n.Cities)
+	if cleaned == "" {
+		return description
+	}
+	for _, a := range n.Aliases {
+		if strings.Contains(strings.ToLower(cleaned), strings.ToLower(a.Match)) ||
+			strings.Contains(strings.ToLower(description), strings.ToLower(a.Match)) {
+			return a.Name
+		}
+	}
+	return TitleCase(cleaned)
+}
+
+// Clean strips the noise from description, including a trailing name from
+// cities, without changing its case.
+func Clean(description string, cities []string) string {
+	s := strings.TrimSpace(description)
+	s = processorPrefix.ReplaceAllString(s, "")
+	if m := locationSuffix.FindStringSubmatchIndex(s); m != nil {
+		if stateCodes[strings.ToUpper(s[m[6]:m[7]])] {
+			s = s[:m[0]]
+		}
+	}
+	s = storeLocation.ReplaceAllString(s, "")
+	s = storeNumber.ReplaceAllString(s, " ")
+	s = trailingNumbers.ReplaceAllString(s, "")
+	s = whitespace.ReplaceAllString(s, " ")
+	s = trimCity(strings.TrimSpace(s), cities)
+	return trailingSeparators.ReplaceAllString(s, "")
+}
+
+// trimCity removes the longest of cities that ends s as whole words, unless
+// nothing would be left of the merchant.
+func trimCity(s string, cities []string) string {
+	longest := ""
+	for _, city := range cities {
+		city = strings.TrimSpace(city)
+		if len(city) <= len(longest) || len(city) >= len(s) || !strings.EqualFold(s[len(s)-len(city):], city) {
+			continue
+		}
+		if before := s[len(s)-len(city)-1]; before == ' ' || before == '-' || before == ',' {
+			longest = city
+		}
+	}
+	return strings.TrimSpace(s[:len(s)-len(longest)])
+}
+
+// TitleCase capitalizes the first letter of each word and lowercases the
+// rest, e.g. "CHICK-FIL-A" becomes "Chick-fil-a".
+func TitleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
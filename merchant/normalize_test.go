@@ -0,0 +1,78 @@
+package merchant
+
+import "testing"
+
+var testCities = []string{"Chapel Hill", "Durham", "Raleigh"}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"WEGMANS CHAPEL HILL #140", "WEGMANS"},
+		{"WEGMANS   CHAPEL HILL NC", "WEGMANS"},
+		{"NEOMONDE, RALEIGH, NC", "NEOMONDE"},
+		{"REI #85 DURHAM", "REI"},
+		{"TST* Neomonde - Durham", "Neomonde"},
+		{"TST* FIVE STAR DURHAM", "FIVE STAR"},
+		{"TST* CAROLINA ALE HOUSE -", "CAROLINA ALE HOUSE"},
+		{"BP#2300671S WILMST FAMIL", "BP"},
+		{"BP#1234567CIRCLE K", "BP CIRCLE K"},
+		{"CIRCLE K # 23101", "CIRCLE K"},
+		{"CHICK-FIL-A #02138", "CHICK-FIL-A"},
+		{"HARRIS TEETER 0120", "HARRIS TEETER"},
+		{"TARGET        00018721", "TARGET"},
+		{"SQ *MILK BAR", "MILK BAR"},
+		{"RALEIGH STREET PARKING", "RALEIGH STREET PARKING"},
+		{"THE RALEIGH TIMES", "THE RALEIGH TIMES"},
+		{"Durham", "Durham"},
+	}
+	for _, test := range tests {
+		if got := Clean(test.description, testCities); got != test.expected {
+			t.Errorf("Clean(%q): expected %q, got %q", test.description, test.expected, got)
+		}
+	}
+}
+
+func TestTrimCity(t *testing.T) {
+	tests := []struct {
+		s        string
+		cities   []string
+		expected string
+	}{
+		{"WEGMANS CHAPEL HILL", testCities, "WEGMANS"},
+		{"WEGMANS HILL", []string{"Hill", "Chapel Hill"}, "WEGMANS"},
+		{"FOO CHAPEL HILL", []string{"Hill", "Chapel Hill"}, "FOO"},
+		{"NEOMONDE-DURHAM", testCities, "NEOMONDE-"},
+		{"NEODURHAM", testCities, "NEODURHAM"},
+		{"DURHAM", testCities, "DURHAM"},
+		{"REI DURHAM", nil, "REI DURHAM"},
+		{"ȺȺ DURHAM", testCities, "ȺȺ"},
+	}
+	for _, test := range tests {
+		if got := trimCity(test.s, test.cities); got != test.expected {
+			t.Errorf("trimCity(%q, %v): expected %q, got %q", test.s, test.cities, test.expected, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	n := &Normalizer{
+		Aliases: []Alias{{Match: "raleigh beer gar", Name: "Raleigh Beer Garden"}},
+		Cities:  testCities,
+	}
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"TST* THE RALEIGH BEER GAR", "Raleigh Beer Garden"},
+		{"WEGMANS CHAPEL HILL #140", "Wegmans"},
+		{"CHICK-FIL-A #02138", "Chick-fil-a"},
+		{"#140", "#140"},
+	}
+	for _, test := range tests {
+		if got := n.Normalize(test.description); got != test.expected {
+			t.Errorf("Normalize(%q): expected %q, got %q", test.description, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact amount of money in cents. Using integer cents instead of
+// float64 keeps sums exact and formatting free of stray digits.
+type Amount int64
+
+func FromCents(cents int64) Amount {
+	return Amount(cents)
+}
+
+func (a Amount) Cents() int64 {
+	return int64(a)
+}
+
+// Parse reads amounts such as 14.40, -14.4, $1,234.56, -$5 or (14.40), where
+// parentheses mean negative. More than two decimal places is an error unless
+// the extra digits are zeros, so no fraction of a cent is silently dropped.
+func Parse(s string) (Amount, error) {
+	original := s
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	s = strings.NewReplacer("$", "", ",", "", " ", "").Replace(s)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = !negative
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("'%s' contains no digits", original)
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if trimmed := strings.TrimRight(fraction, "0"); len(trimmed) > 2 {
+		return 0, fmt.Errorf("'%s' has more than two decimal places", original)
+	}
+	fraction = (fraction + "00")[:2]
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("'%s' is not a valid amount", original)
+		}
+	}
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse '%s' as cents: %w", original, err)
+	}
+	if negative {
+		cents = -cents
+	}
+	return Amount(cents), nil
+}
+
+// String formats a as dollars, e.g. $14.40, -$5.00 or $1,234.56.
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+	}
+	whole, fraction := a.Abs().split()
+	digits := strconv.FormatInt(whole, 10)
+	var grouped strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(r)
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, grouped.String(), fraction)
+}
+
+// Decimal formats a as a plain decimal number with two places, e.g. -1234.56,
+// for file formats and spreadsheets that expect bare numbers.
+func (a Amount) Decimal() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+	}
+	whole, fraction := a.Abs().split()
+	return fmt.Sprintf("%s%d.%02d", sign, whole, fraction)
+}
+
+func (a Amount) split() (whole, fraction int64) {
+	return int64(a) / 100, int64(a) % 100
+}
+
+// Float64 returns a in dollars. It is only meant for handing values to APIs
+// that need a number, such as Sheets; do arithmetic on Amount instead.
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+func (a Amount) Neg() Amount {
+	return -a
+}
+
+func (a Amount) Abs() Amount {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// MarshalJSON writes a as a JSON number with two decimal places.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.Decimal()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string in any format Parse
+// understands.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	var s string
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	} else {
+		s = string(b)
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
@@ -0,0 +1,105 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Amount
+		isValid  bool
+	}{
+		{"14.40", 1440, true},
+		{"-14.4", -1440, true},
+		{"$1,234.56", 123456, true},
+		{"-$5", -500, true},
+		{"(14.40)", -1440, true},
+		{"(-14.40)", 1440, true},
+		{"+3.05", 305, true},
+		{" .5 ", 50, true},
+		{"12.3400", 1234, true},
+		{"0", 0, true},
+		{"12.345", 0, false},
+		{"", 0, false},
+		{"$", 0, false},
+		{"1.2.3", 0, false},
+		{"abc", 0, false},
+		{"1e3", 0, false},
+	}
+	for _, test := range tests {
+		got, err := Parse(test.input)
+		if (err == nil) != test.isValid {
+			t.Errorf("'%s': expected valid %v, got error %v", test.input, test.isValid, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("'%s': expected %d cents, got %d", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		amount          Amount
+		expectedString  string
+		expectedDecimal string
+	}{
+		{0, "$0.00", "0.00"},
+		{5, "$0.05", "0.05"},
+		{-5, "-$0.05", "-0.05"},
+		{1440, "$14.40", "14.40"},
+		{-500, "-$5.00", "-5.00"},
+		{123456, "$1,234.56", "1234.56"},
+		{-123456789, "-$1,234,567.89", "-1234567.89"},
+		{100000, "$1,000.00", "1000.00"},
+	}
+	for _, test := range tests {
+		if got := test.amount.String(); got != test.expectedString {
+			t.Errorf("String of %d cents: expected %s, got %s", test.amount, test.expectedString, got)
+		}
+		if got := test.amount.Decimal(); got != test.expectedDecimal {
+			t.Errorf("Decimal of %d cents: expected %s, got %s", test.amount, test.expectedDecimal, got)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	if got := Sum(FromCents(10), FromCents(20), FromCents(-5)); got != 25 {
+		t.Errorf("Sum: expected 25 cents, got %d", got)
+	}
+	// 0.1 + 0.2 is not 0.3 in float64, but is in cents.
+	if got := FromCents(10).Add(FromCents(20)); got != FromCents(30) {
+		t.Errorf("Add: expected 30 cents, got %d", got)
+	}
+	if got := FromCents(10).Sub(FromCents(25)); got != -15 {
+		t.Errorf("Sub: expected -15 cents, got %d", got)
+	}
+	if got := FromCents(-15).Abs(); got != 15 {
+		t.Errorf("Abs: expected 15 cents, got %d", got)
+	}
+	if got := FromCents(15).Neg().Cents(); got != -15 {
+		t.Errorf("Neg: expected -15 cents, got %d", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	b, err := json.Marshal(FromCents(-1234))
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if string(b) != "-12.34" {
+		t.Errorf("Marshal: expected -12.34, got %s", b)
+	}
+	for _, input := range []string{`-12.34`, `"-$12.34"`, `"(12.34)"`} {
+		var a Amount
+		if err := json.Unmarshal([]byte(input), &a); err != nil {
+			t.Errorf("Unmarshal %s returned error: %v", input, err)
+			continue
+		}
+		if a != -1234 {
+			t.Errorf("Unmarshal %s: expected -1234 cents, got %d", input, a)
+		}
+	}
+}
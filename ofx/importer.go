@@ -0,0 +1,44 @@
+package ofx
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// Importer parses OFX and QFX downloads.
+type Importer struct{}
+
+func (Importer) Name() string {
+	return "OFX"
+}
+
+func (Importer) Confidence(fileName string, contents []byte) float64 {
+	upper := bytes.ToUpper(contents)
+	hasHeader := bytes.Contains(upper, []byte("OFXHEADER"))
+	hasRoot := bytes.Contains(upper, []byte("<OFX>"))
+	switch ext := strings.ToLower(filepath.Ext(fileName)); {
+	case hasRoot && (hasHeader || ext == ".ofx" || ext == ".qfx"):
+		return 1
+	case hasRoot:
+		return 0.8
+	case ext == ".ofx" || ext == ".qfx":
+		return 0.5
+	}
+	return 0
+}
+
+func (Importer) Import(contents []byte) (standard.Statement, error) {
+	statements, err := Parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OFX: %w", err)
+	}
+	var ss standard.Statement
+	for _, s := range statements {
+		ss = append(ss, s.Standardize()...)
+	}
+	return ss, nil
+}
@@ -0,0 +1,158 @@
+package ofx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// element is a node of an OFX document. Leaf elements carry a value and
+// aggregates carry children.
+type element struct {
+	name     string
+	value    string
+	children []*element
+}
+
+var entityReplacer = strings.NewReplacer(
+	"&amp;", "&",
+	"&lt;", "<",
+	"&gt;", ">",
+	"&quot;", `"`,
+	"&apos;", "'",
+	"&nbsp;", " ",
+)
+
+// leafTags are the OFX elements that always carry a value. In SGML they are
+// recognized even when the value is empty, since an empty leaf has no
+// closing tag and would otherwise swallow its following siblings.
+var leafTags = map[string]bool{
+	"ACCTID": true, "ACCTKEY": true, "ACCTTYPE": true, "BALAMT": true,
+	"BANKID": true, "BRANCHID": true, "CHECKNUM": true, "CODE": true,
+	"CORRECTACTION": true, "CORRECTFITID": true, "CURDEF": true,
+	"CURRATE": true, "CURSYM": true, "DTASOF": true, "DTAVAIL": true,
+	"DTEND": true, "DTPOSTED": true, "DTSERVER": true, "DTSTART": true,
+	"DTUSER": true, "EXTDNAME": true, "FID": true, "FITID": true,
+	"LANGUAGE": true, "MEMO": true, "MESSAGE": true, "NAME": true,
+	"ORG": true, "PAYEEID": true, "REFNUM": true, "SEVERITY": true,
+	"SIC": true, "SRVRTID": true, "TRNAMT": true, "TRNTYPE": true,
+	"TRNUID": true,
+}
+
+// parse builds an element tree from an OFX document. It handles both OFX 1.x,
+// which is SGML where leaf elements have no closing tag, and OFX 2.x, which is
+// XML. Everything before the <OFX> root, i.e. the header, is ignored.
+func parse(contents []byte) (*element, error) {
+	doc := string(contents)
+	start := strings.Index(strings.ToUpper(doc), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("no <OFX> root element found")
+	}
+	doc = doc[start:]
+
+	root := &element{name: "ROOT"}
+	stack := []*element{root}
+	for {
+		open := strings.IndexByte(doc, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(doc[open:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated tag near '%s'", truncate(doc[open:], 20))
+		}
+		tag := strings.TrimSpace(doc[open+1 : open+end])
+		doc = doc[open+end+1:]
+
+		switch {
+		case tag == "" || tag[0] == '?' || tag[0] == '!':
+			continue
+		case tag[0] == '/':
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			// Closing tags for SGML leaves were never pushed, so only pop when
+			// the name is actually open.
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		selfClosing := strings.HasSuffix(tag, "/")
+		if fields := strings.Fields(strings.TrimSuffix(tag, "/")); len(fields) > 0 {
+			tag = fields[0]
+		}
+		e := &element{name: strings.ToUpper(tag)}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, e)
+		if selfClosing {
+			continue
+		}
+
+		next := strings.IndexByte(doc, '<')
+		if next < 0 {
+			next = len(doc)
+		}
+		value := strings.TrimSpace(doc[:next])
+		if value != "" || leafTags[e.name] {
+			e.value = entityReplacer.Replace(value)
+			doc = doc[next:]
+			continue
+		}
+		stack = append(stack, e)
+	}
+	return root, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// child returns the first direct child with the given name.
+func (e *element) child(name string) *element {
+	for _, c := range e.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// childValue returns the value of the first direct child with the given name,
+// or an empty string if there is none.
+func (e *element) childValue(name string) string {
+	if c := e.child(name); c != nil {
+		return c.value
+	}
+	return ""
+}
+
+// findAll returns every descendant with the given name, in document order.
+func (e *element) findAll(name string) []*element {
+	var found []*element
+	for _, c := range e.children {
+		if c.name == name {
+			found = append(found, c)
+		}
+		found = append(found, c.findAll(name)...)
+	}
+	return found
+}
+
+// find returns the first descendant with the given name.
+func (e *element) find(name string) *element {
+	for _, c := range e.children {
+		if c.name == name {
+			return c
+		}
+		if found := c.find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package ofx
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func TestParseSGMLWithEmptyLeaves(t *testing.T) {
+	contents := []byte(`OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<CURDEF>USD
+<BANKACCTFROM>
+<BANKID>123456789
+<ACCTID>
+<ACCTTYPE>CHECKING
+</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240105120000
+<MEMO>
+<TRNAMT>-12.34
+<FITID>1
+<NAME>COFFEE SHOP
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240106
+<NAME>
+<TRNAMT>50.00
+<FITID>2
+<MEMO>
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`)
+	statements, err := Parse(contents)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(statements))
+	}
+	s := statements[0]
+	if s.AccountID != "" {
+		t.Errorf("expected empty account ID, got '%s'", s.AccountID)
+	}
+	if len(s.Transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(s.Transactions))
+	}
+	expected := []struct {
+		fitID  string
+		name   string
+		amount money.Amount
+	}{
+		{"1", "COFFEE SHOP", money.FromCents(-1234)},
+		{"2", "", money.FromCents(5000)},
+	}
+	for i, e := range expected {
+		got := s.Transactions[i]
+		if got.FITID != e.fitID || got.Name != e.name || got.Amount != e.amount || got.Memo != "" {
+			t.Errorf("transaction %d: expected FITID '%s', name '%s', amount %s and no memo, got %+v", i, e.fitID, e.name, e.amount, got)
+		}
+	}
+}
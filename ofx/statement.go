@@ -0,0 +1,172 @@
+package ofx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// Transaction is a STMTTRN aggregate. Amount keeps the OFX sign convention:
+// negative for money leaving the account.
+type Transaction struct {
+	FITID      string
+	Type       string
+	DatePosted date.Date
+	Amount     money.Amount
+	Name       string
+	Memo       string
+}
+
+// Statement is a bank (STMTRS) or credit card (CCSTMTRS) statement.
+type Statement struct {
+	AccountID         string
+	LedgerBalance     *money.Amount
+	LedgerBalanceDate date.Date
+	Transactions      []Transaction
+}
+
+// Parse reads every statement in an OFX 1.x or 2.x document.
+func Parse(contents []byte) ([]Statement, error) {
+	root, err := parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OFX document: %w", err)
+	}
+
+	statementElements := append(root.findAll("STMTRS"), root.findAll("CCSTMTRS")...)
+	if len(statementElements) == 0 {
+		return nil, errors.New("document contains no statements")
+	}
+	statements := make([]Statement, 0, len(statementElements))
+	for i, se := range statementElements {
+		s, err := elementToStatement(se)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read statement %d: %w", i+1, err)
+		}
+		statements = append(statements, s)
+	}
+	return statements, nil
+}
+
+func elementToStatement(se *element) (s Statement, err error) {
+	for _, accountName := range []string{"BANKACCTFROM", "CCACCTFROM"} {
+		if account := se.child(accountName); account != nil {
+			s.AccountID = account.childValue("ACCTID")
+		}
+	}
+
+	if ledger := se.child("LEDGERBAL"); ledger != nil {
+		balance, err := parseAmount(ledger.childValue("BALAMT"))
+		if err != nil {
+			return s, fmt.Errorf("failed to parse ledger balance: %w", err)
+		}
+		s.LedgerBalance = &balance
+		if dateAsOf := ledger.childValue("DTASOF"); dateAsOf != "" {
+			s.LedgerBalanceDate, err = parseDate(dateAsOf)
+			if err != nil {
+				return s, fmt.Errorf("failed to parse ledger balance date: %w", err)
+			}
+		}
+	}
+
+	for i, te := range se.findAll("STMTTRN") {
+		t, err := elementToTransaction(te)
+		if err != nil {
+			return s, fmt.Errorf("failed to read transaction %d: %w", i+1, err)
+		}
+		s.Transactions = append(s.Transactions, t)
+	}
+	return s, nil
+}
+
+func elementToTransaction(te *element) (t Transaction, err error) {
+	t.FITID = te.childValue("FITID")
+	t.Type = te.childValue("TRNTYPE")
+	t.Name = te.childValue("NAME")
+	t.Memo = te.childValue("MEMO")
+	t.DatePosted, err = parseDate(te.childValue("DTPOSTED"))
+	if err != nil {
+		return t, fmt.Errorf("failed to parse DTPOSTED: %w", err)
+	}
+	t.Amount, err = parseAmount(te.childValue("TRNAMT"))
+	if err != nil {
+		return t, fmt.Errorf("failed to parse TRNAMT: %w", err)
+	}
+	return t, nil
+}
+
+// parseAmount parses an OFX amount, which may use a comma as the decimal
+// separator.
+func parseAmount(s string) (money.Amount, error) {
+	return money.Parse(strings.ReplaceAll(s, ",", "."))
+}
+
+// parseDate reads the date part of an OFX datetime such as
+// 20230103120000.000[-5:EST]. The time and time zone are ignored.
+func parseDate(s string) (date.Date, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < len(date.OFXLayout) {
+		return date.Date{}, fmt.Errorf("'%s' is too short to be an OFX date", s)
+	}
+	return date.Parse(s[:len(date.OFXLayout)], date.OFXLayout)
+}
+
+// Standardize converts s into a standard statement, a transaction per STMTTRN.
+// The ledger balance becomes the balance of the latest transaction.
+func (s Statement) Standardize() standard.Statement {
+	ss := make(standard.Statement, 0, len(s.Transactions))
+	for i, t := range s.Transactions {
+		st := t.standardize()
+		st.Provenance.Row = i + 1
+		// The account ID stands in for the account until it is matched with
+		// a configured account.
+		st.Account = s.AccountID
+		// FITIDs are only unique within an account.
+		if t.FITID != "" {
+			st.Fingerprint = fmt.Sprintf("ofx|%s|%s", s.AccountID, t.FITID)
+		}
+		ss = append(ss, st)
+	}
+	if latest := s.latestTransaction(); latest >= 0 && s.LedgerBalance != nil {
+		balance := *s.LedgerBalance
+		ss[latest].Balance = &balance
+	}
+	return ss
+}
+
+// latestTransaction returns the index of the last of the transactions
+// posted on the latest date no later than the ledger balance date, or -1 if
+// there is none. Banks list transactions either oldest or newest first, so
+// the order of the document says nothing by itself.
+func (s Statement) latestTransaction() int {
+	latest := -1
+	for i, t := range s.Transactions {
+		if !s.LedgerBalanceDate.IsZero() && t.DatePosted.After(s.LedgerBalanceDate) {
+			continue
+		}
+		if latest < 0 || !t.DatePosted.Before(s.Transactions[latest].DatePosted) {
+			latest = i
+		}
+	}
+	return latest
+}
+
+func (t Transaction) standardize() standard.Transaction {
+	description := t.Name
+	if description == "" {
+		description = t.Memo
+	}
+	return standard.Transaction{
+		Date:        t.DatePosted,
+		Description: description,
+		Amount:      t.Amount.Neg(),
+		Provenance: standard.Provenance{
+			PostedDate: t.DatePosted,
+			ItemType:   t.Type,
+			Memo:       t.Memo,
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package ofx
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func TestStandardize(t *testing.T) {
+	balance := money.FromCents(123456)
+	s := Statement{
+		AccountID:         "9876",
+		LedgerBalance:     &balance,
+		LedgerBalanceDate: mustDate(t, "2024-01-31"),
+		Transactions: []Transaction{
+			{FITID: "3", DatePosted: mustDate(t, "2024-02-01"), Amount: money.FromCents(-100), Name: "PENDING"},
+			{FITID: "2", DatePosted: mustDate(t, "2024-01-20"), Amount: money.FromCents(250000), Name: "PAYROLL"},
+			{FITID: "1", DatePosted: mustDate(t, "2024-01-05"), Amount: money.FromCents(-1234), Memo: "COFFEE SHOP"},
+		},
+	}
+	ss := s.Standardize()
+	expected := []struct {
+		description string
+		amount      money.Amount
+		fingerprint string
+		hasBalance  bool
+	}{
+		{"PENDING", money.FromCents(100), "ofx|9876|3", false},
+		{"PAYROLL", money.FromCents(-250000), "ofx|9876|2", true},
+		{"COFFEE SHOP", money.FromCents(1234), "ofx|9876|1", false},
+	}
+	if len(ss) != len(expected) {
+		t.Fatalf("expected %d transactions, got %d", len(expected), len(ss))
+	}
+	for i, e := range expected {
+		st := ss[i]
+		if st.Description != e.description || st.Amount != e.amount || st.Fingerprint != e.fingerprint || st.Account != "9876" || (st.Balance != nil) != e.hasBalance {
+			t.Errorf("transaction %d: expected %+v, got %+v", i, e, st)
+		}
+	}
+	if ss[1].Balance == nil || *ss[1].Balance != balance {
+		t.Errorf("expected the ledger balance %s on the latest transaction", balance)
+	}
+}
+
+func mustDate(t *testing.T, s string) date.Date {
+	t.Helper()
+	d, err := date.Parse(s, date.ISOLayout)
+	if err != nil {
+		t.Fatalf("failed to parse date %s: %v", s, err)
+	}
+	return d
+}
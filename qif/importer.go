@@ -0,0 +1,50 @@
+package qif
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// Importer parses Quicken Interchange Format files. TransferCategory is the
+// category transfers between accounts are filed under.
+type Importer struct {
+	TransferCategory string
+}
+
+func (Importer) Name() string {
+	return "QIF"
+}
+
+func (Importer) Confidence(fileName string, contents []byte) float64 {
+	startsWithType := bytes.HasPrefix(bytes.TrimSpace(contents), []byte("!Type:"))
+	isQifExtension := strings.ToLower(filepath.Ext(fileName)) == ".qif"
+	switch {
+	case startsWithType && isQifExtension:
+		return 1
+	case startsWithType:
+		return 0.8
+	case isQifExtension:
+		return 0.5
+	}
+	return 0
+}
+
+func (i Importer) Import(contents []byte) (standard.Statement, error) {
+	sections, err := Parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse QIF: %w", err)
+	}
+	if len(sections) == 0 {
+		return nil, errors.New("file contains no bank or credit card sections")
+	}
+	var ss standard.Statement
+	for _, s := range sections {
+		ss = append(ss, s.Standardize(i.TransferCategory)...)
+	}
+	return ss, nil
+}
@@ -0,0 +1,183 @@
+package qif
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// Split is one S/E/$ group of a split transaction.
+type Split struct {
+	Category string
+	Memo     string
+	Amount   money.Amount
+}
+
+// Transaction is a single record of a bank or credit card section. Amounts
+// keep the QIF sign convention: negative for money leaving the account.
+type Transaction struct {
+	Date     date.Date
+	Amount   money.Amount
+	Payee    string
+	Memo     string
+	Category string
+	Number   string
+	Cleared  string
+	Splits   []Split
+	// Line is the line of the file the record starts on.
+	Line int
+}
+
+// Section is the list of transactions following one !Type header.
+type Section struct {
+	Type         string
+	Transactions []Transaction
+}
+
+// supportedTypes are the !Type headers holding transactions we understand.
+// Investment, memorized and category list sections are skipped.
+var supportedTypes = map[string]bool{
+	"Bank":  true,
+	"CCard": true,
+	"Cash":  true,
+	"Oth A": true,
+	"Oth L": true,
+}
+
+// Parse reads the bank and credit card sections of a QIF file.
+func Parse(contents []byte) ([]Section, error) {
+	var sections []Section
+	var current *Section
+	var t Transaction
+	var inRecord bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] == '!' {
+			if inRecord {
+				return nil, fmt.Errorf("line %d: header found before end of previous record", lineNum)
+			}
+			current = nil
+			if typeName, ok := strings.CutPrefix(line, "!Type:"); ok {
+				typeName = strings.TrimSpace(typeName)
+				if supportedTypes[typeName] {
+					sections = append(sections, Section{Type: typeName})
+					current = &sections[len(sections)-1]
+				}
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		code, value := line[0], strings.TrimSpace(line[1:])
+		if code == '^' {
+			current.Transactions = append(current.Transactions, t)
+			t = Transaction{}
+			inRecord = false
+			continue
+		}
+		if !inRecord {
+			t.Line = lineNum
+		}
+		inRecord = true
+		if err := t.setField(code, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan file: %w", err)
+	}
+	if inRecord {
+		return nil, fmt.Errorf("last record is missing its ^ terminator")
+	}
+	return sections, nil
+}
+
+func (t *Transaction) setField(code byte, value string) (err error) {
+	switch code {
+	case 'D':
+		t.Date, err = parseDate(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse date: %w", err)
+		}
+	case 'T', 'U':
+		t.Amount, err = money.Parse(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse amount: %w", err)
+		}
+	case 'P':
+		t.Payee = value
+	case 'M':
+		t.Memo = value
+	case 'L':
+		t.Category = value
+	case 'N':
+		t.Number = value
+	case 'C':
+		t.Cleared = value
+	case 'S':
+		t.Splits = append(t.Splits, Split{Category: value})
+	case 'E', '$':
+		if len(t.Splits) == 0 {
+			return fmt.Errorf("split field %c found before any S line", code)
+		}
+		split := &t.Splits[len(t.Splits)-1]
+		if code == 'E' {
+			split.Memo = value
+			break
+		}
+		split.Amount, err = money.Parse(value)
+		if err != nil {
+			return fmt.Errorf("failed to parse split amount: %w", err)
+		}
+	}
+	// Other fields, such as addresses (A) and percentages (%), are ignored.
+	return nil
+}
+
+// parseDate reads QIF dates such as 1/3/2023, 01/03/23, 1/ 3'23 or
+// 01-03-2023. Quicken marks years from 2000 on with an
+// apostrophe when it writes two-digit years.
+func parseDate(s string) (date.Date, error) {
+	normalized := strings.NewReplacer(" ", "", "-", "/", ".", "/").Replace(s)
+	apostropheYear := strings.Contains(normalized, "'")
+	normalized = strings.Replace(normalized, "'", "/", 1)
+
+	parts := strings.Split(normalized, "/")
+	if len(parts) != 3 {
+		return date.Date{}, fmt.Errorf("'%s' does not have a month, day and year", s)
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return date.Date{}, fmt.Errorf("failed to parse '%s' in '%s' as an integer: %w", part, s, err)
+		}
+		nums[i] = num
+	}
+	month, day, year := nums[0], nums[1], nums[2]
+	if len(parts[2]) <= 2 {
+		switch {
+		case apostropheYear, year < 70:
+			year += 2000
+		default:
+			year += 1900
+		}
+	}
+	return date.New(year, time.Month(month), day)
+}
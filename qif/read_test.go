@@ -0,0 +1,122 @@
+package qif
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		s         string
+		expected  string
+		expectErr bool
+	}{
+		{"1/3/2023", "2023-01-03", false},
+		{"01/03/23", "2023-01-03", false},
+		{"1/ 3'23", "2023-01-03", false},
+		{"01-03-2023", "2023-01-03", false},
+		{"12/31/99", "1999-12-31", false},
+		{"1/3", "", true},
+		{"2/30/2023", "", true},
+	}
+	for _, test := range tests {
+		d, err := parseDate(test.s)
+		if (err != nil) != test.expectErr {
+			t.Errorf("parseDate(%q): expected error %v, got %v", test.s, test.expectErr, err)
+			continue
+		}
+		if !test.expectErr && d.Format("2006-01-02") != test.expected {
+			t.Errorf("parseDate(%q): expected %s, got %s", test.s, test.expected, d.Format("2006-01-02"))
+		}
+	}
+}
+
+const testQIF = `!Type:Bank
+D1/3/2023
+T-60.19
+PWEGMANS
+MWeekly shop
+LGroceries/Toiletries
+^
+D1/5/2023
+T-100.00
+PTARGET
+SGroceries/Toiletries
+EFood
+$-70.00
+SGift Giving
+$-30.00
+^
+D1/6/2023
+T-500.00
+PTRANSFER TO SAVINGS
+L[Savings]
+^
+!Type:Memorized
+KC
+PIGNORED
+^
+`
+
+func TestParse(t *testing.T) {
+	sections, err := Parse([]byte(testQIF))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(sections) != 1 || sections[0].Type != "Bank" {
+		t.Fatalf("expected one Bank section, got %+v", sections)
+	}
+	transactions := sections[0].Transactions
+	if len(transactions) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(transactions))
+	}
+	first := transactions[0]
+	if first.Payee != "WEGMANS" || first.Memo != "Weekly shop" || first.Category != "Groceries/Toiletries" || first.Amount != money.FromCents(-6019) || first.Line != 2 {
+		t.Errorf("unexpected first transaction %+v", first)
+	}
+	splits := transactions[1].Splits
+	if len(splits) != 2 || splits[0].Memo != "Food" || splits[0].Amount != money.FromCents(-7000) || splits[1].Category != "Gift Giving" {
+		t.Errorf("unexpected splits %+v", splits)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"missing terminator", "!Type:Bank\nD1/3/2023\nT-1.00\n"},
+		{"bad amount", "!Type:Bank\nD1/3/2023\nTabc\n^\n"},
+		{"split amount before S", "!Type:Bank\nD1/3/2023\n$-1.00\n^\n"},
+		{"header inside record", "!Type:Bank\nD1/3/2023\n!Type:CCard\n^\n"},
+	}
+	for _, test := range tests {
+		if _, err := Parse([]byte(test.contents)); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestStandardize(t *testing.T) {
+	sections, err := Parse([]byte(testQIF))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	s := sections[0].Standardize("Transfer")
+	if len(s) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(s))
+	}
+	if s[0].Amount != money.FromCents(6019) || s[0].Category != "Groceries/Toiletries" || s[0].Provenance.Memo != "Weekly shop" {
+		t.Errorf("unexpected first transaction %+v", s[0])
+	}
+	if len(s[1].Splits) != 2 || s[1].Splits[0].Description != "TARGET - Food" || s[1].Splits[0].Amount != money.FromCents(7000) || s[1].Splits[1].Description != "" {
+		t.Errorf("unexpected splits %+v", s[1].Splits)
+	}
+	if s[2].Category != "Transfer" || s[2].Provenance.SourceCategory != "[Savings]" {
+		t.Errorf("expected the transfer to be filed under Transfer, got %+v", s[2])
+	}
+	if untransferred := sections[0].Standardize(""); untransferred[2].Category != "" {
+		t.Errorf("expected no category without a transfer category, got %s", untransferred[2].Category)
+	}
+}
@@ -0,0 +1,72 @@
+package qif
+
+import (
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// Standardize converts the section into a standard statement. Split
+// records become split transactions with a line per split, and transfers to
+// other accounts are filed under transferCategory, or left uncategorized if
+// it is empty.
+func (s Section) Standardize(transferCategory string) standard.Statement {
+	ss := make(standard.Statement, 0, len(s.Transactions))
+	for _, t := range s.Transactions {
+		ss = append(ss, t.standardize(transferCategory))
+	}
+	return ss
+}
+
+// splitDescriptionSeparator joins a record's payee and a split line's memo
+// into the split line's description.
+const splitDescriptionSeparator = " - "
+
+func (t Transaction) standardize(transferCategory string) standard.Transaction {
+	description := t.Payee
+	if description == "" {
+		description = t.Memo
+	}
+	st := standard.Transaction{
+		Date:        t.Date,
+		Category:    categoryOf(t.Category, transferCategory),
+		Description: description,
+		Amount:      t.Amount.Neg(),
+		Provenance: standard.Provenance{
+			Row:            t.Line,
+			Memo:           t.Memo,
+			SourceCategory: t.Category,
+		},
+	}
+	if len(t.Splits) == 0 {
+		return st
+	}
+
+	splits := make([]standard.Split, 0, len(t.Splits))
+	for _, split := range t.Splits {
+		var splitDescription string
+		if split.Memo != "" {
+			splitDescription = description + splitDescriptionSeparator + split.Memo
+		}
+		splits = append(splits, standard.Split{
+			Category:    categoryOf(split.Category, transferCategory),
+			Description: splitDescription,
+			Amount:      split.Amount.Neg(),
+		})
+	}
+	// Quicken allows split records whose lines do not add up or that have a
+	// single line. Those keep the record's own category instead.
+	if err := st.SetSplits(splits); err != nil && st.Category == "" {
+		st.Category = splits[0].Category
+	}
+	return st
+}
+
+// categoryOf is the category of a QIF category field, which names another
+// account in brackets, as in "[Savings]", for transfers.
+func categoryOf(field, transferCategory string) string {
+	if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+		return transferCategory
+	}
+	return field
+}
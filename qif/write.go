@@ -0,0 +1,46 @@
+package qif
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// Write writes s as a single QIF section of the given type, e.g. "Bank" or
+// "CCard".
+func Write(w io.Writer, s standard.Statement, sectionType string) error {
+	if !supportedTypes[sectionType] {
+		return fmt.Errorf("'%s' is not a supported section type", sectionType)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "!Type:%s\n", sectionType)
+	for _, t := range s {
+		fmt.Fprintf(bw, "D%s\n", t.Date.Format(date.USLayout))
+		fmt.Fprintf(bw, "T%s\n", t.Amount.Neg().Decimal())
+		fmt.Fprintf(bw, "P%s\n", t.Description)
+		if t.Provenance.Memo != "" {
+			fmt.Fprintf(bw, "M%s\n", t.Provenance.Memo)
+		}
+		if t.Category != "" && len(t.Splits) == 0 {
+			fmt.Fprintf(bw, "L%s\n", t.Category)
+		}
+		for _, split := range t.Splits {
+			fmt.Fprintf(bw, "S%s\n", split.Category)
+			// Split descriptions read from QIF start with the payee, which
+			// the memo leaves out.
+			if memo := strings.TrimPrefix(split.Description, t.Description+splitDescriptionSeparator); memo != "" {
+				fmt.Fprintf(bw, "E%s\n", memo)
+			}
+			fmt.Fprintf(bw, "$%s\n", split.Amount.Neg().Decimal())
+		}
+		fmt.Fprintf(bw, "^\n")
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package qif
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	sections, err := Parse([]byte(testQIF))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	s := sections[0].Standardize("Transfer")
+
+	var first bytes.Buffer
+	if err = Write(&first, s, "Bank"); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	rereadSections, err := Parse(first.Bytes())
+	if err != nil {
+		t.Fatalf("Parse of written QIF returned error: %v\n%s", err, first.String())
+	}
+	reread := rereadSections[0].Standardize("Transfer")
+	var second bytes.Buffer
+	if err = Write(&second, reread, "Bank"); err != nil {
+		t.Fatalf("second Write returned error: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("writing what was read changed the file:\n%s\nbecame\n%s", first.String(), second.String())
+	}
+
+	if len(reread) != len(s) {
+		t.Fatalf("expected %d transactions, got %d", len(s), len(reread))
+	}
+	for i := range s {
+		a, b := s[i], reread[i]
+		if a.Date != b.Date || a.Amount != b.Amount || a.Description != b.Description || a.Category != b.Category || a.Provenance.Memo != b.Provenance.Memo || len(a.Splits) != len(b.Splits) {
+			t.Errorf("transaction %d: expected %+v, got %+v", i, a, b)
+			continue
+		}
+		for j := range a.Splits {
+			if a.Splits[j] != b.Splits[j] {
+				t.Errorf("transaction %d split %d: expected %+v, got %+v", i, j, a.Splits[j], b.Splits[j])
+			}
+		}
+	}
+}
+
+func TestWriteUnsupportedType(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, nil, "Invst"); err == nil {
+		t.Errorf("expected an error for an unsupported section type")
+	}
+}
@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// Transaction is everything a rule can look at. Amount follows the standard
+// convention of money spent being positive. Description is the normalized
+// merchant name and OriginalDescription the bank's text; description
+// patterns are tried on both, so keywords written against either keep
+// working.
+type Transaction struct {
+	Description         string
+	OriginalDescription string
+	Amount              money.Amount
+	Date                date.Date
+	SourceCategory      string
+	ItemType            string
+}
+
+// Condition is a test on a transaction. All the fields that are set must
+// hold for the condition to hold, so a single condition is an AND of its
+// fields; Any and Not give OR and negation.
+type Condition struct {
+	Pattern string    `json:"pattern"`
+	Match   MatchType `json:"match"`
+	// AmountMin and AmountMax bound the amount, inclusive.
+	AmountMin *money.Amount `json:"amount_min"`
+	AmountMax *money.Amount `json:"amount_max"`
+	// SourceCategory and ItemType are compared, ignoring case, with the
+	// category and type the bank gave the transaction, e.g. Chase's
+	// "Groceries" and "Sale".
+	SourceCategory string `json:"source_category"`
+	ItemType       string `json:"item_type"`
+	// Weekdays are English day names or their three-letter abbreviations.
+	Weekdays []string  `json:"weekdays"`
+	DateFrom date.Date `json:"date_from"`
+	DateTo   date.Date `json:"date_to"`
+
+	All []Condition `json:"all"`
+	Any []Condition `json:"any"`
+	Not *Condition  `json:"not"`
+
+	description *pattern
+	weekdays    map[time.Weekday]bool
+}
+
+var weekdaysByName = map[string]time.Weekday{}
+
+func init() {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		weekdaysByName[strings.ToLower(d.String())] = d
+		weekdaysByName[strings.ToLower(d.String()[:3])] = d
+	}
+}
+
+func (c *Condition) compile() (err error) {
+	if c.Pattern != "" {
+		if c.description, err = compilePattern(c.Pattern, c.Match); err != nil {
+			return fmt.Errorf("failed to compile pattern '%s': %w", c.Pattern, err)
+		}
+	}
+	if c.AmountMin != nil && c.AmountMax != nil && *c.AmountMin > *c.AmountMax {
+		return fmt.Errorf("amount_min %s is more than amount_max %s", c.AmountMin, c.AmountMax)
+	}
+	if !c.DateFrom.IsZero() && !c.DateTo.IsZero() && c.DateTo.Before(c.DateFrom) {
+		return fmt.Errorf("date_to %s is before date_from %s", c.DateTo, c.DateFrom)
+	}
+	if len(c.Weekdays) > 0 {
+		c.weekdays = map[time.Weekday]bool{}
+		for _, name := range c.Weekdays {
+			d, ok := weekdaysByName[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("'%s' is not a day of the week", name)
+			}
+			c.weekdays[d] = true
+		}
+	}
+	for i := range c.All {
+		if err = c.All[i].compile(); err != nil {
+			return fmt.Errorf("failed to compile 'all' condition %d: %w", i+1, err)
+		}
+	}
+	for i := range c.Any {
+		if err = c.Any[i].compile(); err != nil {
+			return fmt.Errorf("failed to compile 'any' condition %d: %w", i+1, err)
+		}
+	}
+	if c.Not != nil {
+		if err = c.Not.compile(); err != nil {
+			return fmt.Errorf("failed to compile 'not' condition: %w", err)
+		}
+	}
+	if c.isEmpty() {
+		return errors.New("condition tests nothing")
+	}
+	return nil
+}
+
+func (c *Condition) isEmpty() bool {
+	return c.Pattern == "" && c.AmountMin == nil && c.AmountMax == nil &&
+		c.SourceCategory == "" && c.ItemType == "" && len(c.Weekdays) == 0 &&
+		c.DateFrom.IsZero() && c.DateTo.IsZero() &&
+		len(c.All) == 0 && len(c.Any) == 0 && c.Not == nil
+}
+
+func (c *Condition) holds(t Transaction) bool {
+	if c.description != nil {
+		if _, ok := c.description.matchedTextOf(t); !ok {
+			return false
+		}
+	}
+	if c.AmountMin != nil && t.Amount < *c.AmountMin {
+		return false
+	}
+	if c.AmountMax != nil && t.Amount > *c.AmountMax {
+		return false
+	}
+	if c.SourceCategory != "" && !strings.EqualFold(c.SourceCategory, t.SourceCategory) {
+		return false
+	}
+	if c.ItemType != "" && !strings.EqualFold(c.ItemType, t.ItemType) {
+		return false
+	}
+	if c.weekdays != nil && !c.weekdays[t.Date.Weekday()] {
+		return false
+	}
+	if !c.DateFrom.IsZero() && t.Date.Before(c.DateFrom) {
+		return false
+	}
+	if !c.DateTo.IsZero() && t.Date.After(c.DateTo) {
+		return false
+	}
+	for i := range c.All {
+		if !c.All[i].holds(t) {
+			return false
+		}
+	}
+	if len(c.Any) > 0 {
+		anyHolds := false
+		for i := range c.Any {
+			if c.Any[i].holds(t) {
+				anyHolds = true
+				break
+			}
+		}
+		if !anyHolds {
+			return false
+		}
+	}
+	if c.Not != nil && c.Not.holds(t) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+)
+
+func mustDate(t *testing.T, s string) date.Date {
+	t.Helper()
+	d, err := date.Parse(s, date.ISOLayout)
+	if err != nil {
+		t.Fatalf("failed to parse date %s: %v", s, err)
+	}
+	return d
+}
+
+func TestConditionHolds(t *testing.T) {
+	// 2024-01-06 is a Saturday.
+	saturdayLunch := Transaction{
+		Description:    "NEOMONDE",
+		Amount:         1450,
+		Date:           mustDate(t, "2024-01-06"),
+		SourceCategory: "Food & Drink",
+		ItemType:       "Sale",
+	}
+	tests := []struct {
+		name      string
+		condition string
+		expected  bool
+	}{
+		{"amount in range", `{"amount_min": 10, "amount_max": "$20.00"}`, true},
+		{"amount bounds are inclusive", `{"amount_min": 14.50, "amount_max": 14.50}`, true},
+		{"amount above max", `{"amount_max": 14.49}`, false},
+		{"amount below min", `{"amount_min": 14.51}`, false},
+		{"source category ignores case", `{"source_category": "food & drink"}`, true},
+		{"other source category", `{"source_category": "Groceries"}`, false},
+		{"item type", `{"item_type": "SALE"}`, true},
+		{"other item type", `{"item_type": "Return"}`, false},
+		{"weekend", `{"weekdays": ["sat", "Sunday"]}`, true},
+		{"weekday", `{"weekdays": ["Mon", "Tue", "Wed", "Thu", "Fri"]}`, false},
+		{"date range", `{"date_from": "2024-01-01", "date_to": "01/06/2024"}`, true},
+		{"before date range", `{"date_from": "2024-01-07"}`, false},
+		{"after date range", `{"date_to": "2024-01-05"}`, false},
+		{"pattern and amount", `{"pattern": "neomonde", "amount_max": 20}`, true},
+		{"pattern fails", `{"pattern": "wegmans", "amount_max": 20}`, false},
+		{"all", `{"all": [{"item_type": "Sale"}, {"amount_min": 10}]}`, true},
+		{"all with one failing", `{"all": [{"item_type": "Sale"}, {"amount_min": 100}]}`, false},
+		{"any", `{"any": [{"item_type": "Return"}, {"amount_min": 10}]}`, true},
+		{"any with none holding", `{"any": [{"item_type": "Return"}, {"amount_min": 100}]}`, false},
+		{"not", `{"not": {"item_type": "Return"}}`, true},
+		{"not holding", `{"not": {"item_type": "Sale"}}`, false},
+	}
+	for _, test := range tests {
+		var c Condition
+		if err := json.Unmarshal([]byte(test.condition), &c); err != nil {
+			t.Errorf("%s: failed to unmarshal condition: %v", test.name, err)
+			continue
+		}
+		if err := c.compile(); err != nil {
+			t.Errorf("%s: failed to compile condition: %v", test.name, err)
+			continue
+		}
+		if got := c.holds(saturdayLunch); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestConditionCompileErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+	}{
+		{"empty", `{}`},
+		{"empty nested", `{"not": {}}`},
+		{"amount range reversed", `{"amount_min": 20, "amount_max": 10}`},
+		{"date range reversed", `{"date_from": "2024-02-01", "date_to": "2024-01-01"}`},
+		{"unknown weekday", `{"weekdays": ["Caturday"]}`},
+		{"bad pattern", `{"pattern": "(", "match": "regex"}`},
+		{"bad nested pattern", `{"any": [{"pattern": "x", "match": "glob"}]}`},
+	}
+	for _, test := range tests {
+		var c Condition
+		if err := json.Unmarshal([]byte(test.condition), &c); err != nil {
+			t.Errorf("%s: failed to unmarshal condition: %v", test.name, err)
+			continue
+		}
+		if err := c.compile(); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestConditionalRules(t *testing.T) {
+	engine, err := NewEngine([]Rule{
+		{Pattern: "target", Category: "Home"},
+		{Pattern: "target", Category: "Groceries", When: &Condition{SourceCategory: "Groceries"}, Priority: 1},
+		{Category: "Refunds", When: &Condition{ItemType: "Return"}, Priority: 2},
+	})
+	if err != nil {
+		t.Fatalf("NewEngine returned error: %v", err)
+	}
+	tests := []struct {
+		t        Transaction
+		expected string
+	}{
+		{Transaction{Description: "TARGET 00018721", Amount: 2500, SourceCategory: "Shopping", ItemType: "Sale"}, "Home"},
+		{Transaction{Description: "TARGET 00018721", Amount: 2500, SourceCategory: "Groceries", ItemType: "Sale"}, "Groceries"},
+		{Transaction{Description: "TARGET 00018721", Amount: -2500, SourceCategory: "Groceries", ItemType: "Return"}, "Refunds"},
+		{Transaction{Description: "WEGMANS", Amount: 2500, SourceCategory: "Groceries", ItemType: "Sale"}, ""},
+	}
+	for _, test := range tests {
+		result, ok := engine.Match(test.t)
+		if ok != (test.expected != "") || result.Category() != test.expected {
+			t.Errorf("%s (%s, %s): expected %q, got %q", test.t.Description, test.t.SourceCategory, test.t.ItemType, test.expected, result.Category())
+		}
+	}
+}
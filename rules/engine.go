@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Jack-Timothy/sheets-client/ahocorasick"
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/keywords"
+)
+
+// Engine picks the category of a transaction from an ordered list of rules.
+// Unlike searching a map, the same rules always give the same answer.
+type Engine struct {
+	rules []Rule
+	// index finds the contains patterns in a description in one pass. It is
+	// built on the first Match after the rules change.
+	index *index
+}
+
+// index splits the rules into plain ASCII contains patterns, which the
+// automaton finds together, and the rest, which are tried one by one.
+type index struct {
+	contains *ahocorasick.Matcher
+	// containsRules maps each automaton pattern to its rule.
+	containsRules []int
+	otherRules    []int
+}
+
+// Result is the outcome of a successful match.
+type Result struct {
+	Rule        Rule
+	MatchedText string
+}
+
+func (r Result) Category() string {
+	return r.Rule.Category
+}
+
+func NewEngine(rules []Rule) (*Engine, error) {
+	e := &Engine{rules: make([]Rule, len(rules))}
+	copy(e.rules, rules)
+	for i := range e.rules {
+		if err := e.rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("failed to compile rule %d: %w", i+1, err)
+		}
+	}
+	return e, nil
+}
+
+// NewEngineFromFiles builds an engine from the rules in rulesFileName, if it
+// is not empty, followed by one rule per keyword in keywordsFileName. Every
+// rule must file transactions under one of categories.
+func NewEngineFromFiles(rulesFileName, keywordsFileName string, categories category.List) (*Engine, error) {
+	var rules []Rule
+	if rulesFileName != "" {
+		fileRules, err := RulesFromFile(rulesFileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rules from %s: %w", rulesFileName, err)
+		}
+		rules = append(rules, fileRules...)
+	}
+	kwMap, err := keywords.MapFromFile(keywordsFileName, categories)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make keyword map from %s: %w", keywordsFileName, err)
+	}
+	rules = append(rules, RulesFromKeywords(kwMap)...)
+	for _, r := range rules {
+		for _, c := range r.Categories() {
+			if _, ok := categories.ByName(c); !ok {
+				return nil, fmt.Errorf("rule '%s' has unknown category %s", r.Name, c)
+			}
+		}
+	}
+	return NewEngine(rules)
+}
+
+// Add compiles r and appends it to the engine's rules.
+func (e *Engine) Add(r Rule) error {
+	if err := r.compile(); err != nil {
+		return fmt.Errorf("failed to compile rule: %w", err)
+	}
+	e.rules = append(e.rules, r)
+	e.index = nil
+	return nil
+}
+
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Match returns the winning rule for t. The rule with the highest priority
+// wins; ties go to the longest matched text and then to the rule listed
+// first.
+func (e *Engine) Match(t Transaction) (result Result, found bool) {
+	if e.index == nil {
+		e.index = newIndex(e.rules)
+	}
+	containsTexts := e.index.containsMatches(t)
+	candidates := make([]int, 0, len(e.index.otherRules)+len(containsTexts))
+	candidates = append(candidates, e.index.otherRules...)
+	for i := range containsTexts {
+		candidates = append(candidates, i)
+	}
+	sort.Ints(candidates)
+
+	for _, i := range candidates {
+		r := e.rules[i]
+		text, ok := containsTexts[i]
+		if ok {
+			ok = r.When == nil || r.When.holds(t)
+		} else {
+			text, ok = r.matchedText(t)
+		}
+		if !ok {
+			continue
+		}
+		candidate := Result{Rule: r, MatchedText: text}
+		if !found || candidate.beats(result) {
+			result, found = candidate, true
+		}
+	}
+	return result, found
+}
+
+func newIndex(rules []Rule) *index {
+	idx := &index{}
+	var patterns []string
+	for i, r := range rules {
+		if r.Match == Contains && r.Pattern != "" && isASCII(r.Pattern) {
+			patterns = append(patterns, r.Pattern)
+			idx.containsRules = append(idx.containsRules, i)
+			continue
+		}
+		idx.otherRules = append(idx.otherRules, i)
+	}
+	idx.contains = ahocorasick.New(patterns)
+	return idx
+}
+
+// containsMatches returns the text each matching contains rule matched,
+// keyed by rule. Like pattern.matchedTextOf, it takes the first occurrence
+// in the description and falls back to the original description.
+func (idx *index) containsMatches(t Transaction) map[int]string {
+	texts := map[int]string{}
+	idx.addMatches(texts, t.Description)
+	if t.OriginalDescription != "" && t.OriginalDescription != t.Description {
+		idx.addMatches(texts, t.OriginalDescription)
+	}
+	return texts
+}
+
+func (idx *index) addMatches(texts map[int]string, description string) {
+	for _, m := range idx.contains.FindAll(description) {
+		rule := idx.containsRules[m.Pattern]
+		if _, ok := texts[rule]; !ok {
+			texts[rule] = description[m.Start:m.End]
+		}
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r Result) beats(other Result) bool {
+	if r.Rule.Priority != other.Rule.Priority {
+		return r.Rule.Priority > other.Rule.Priority
+	}
+	return len(r.MatchedText) > len(other.MatchedText)
+}
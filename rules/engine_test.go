@@ -0,0 +1,64 @@
+package rules_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/rules"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// buildMatchTest makes numRules keyword rules and a synthetic statement of
+// numTransactions, about half of whose descriptions contain a keyword.
+func buildMatchTest(tb testing.TB, numRules, numTransactions int) (*rules.Engine, []rules.Transaction) {
+	tb.Helper()
+	categories := []string{"Groceries/Toiletries", "Food/Drinks Out", "Gas", "Other (Want)"}
+	keywordRules := make([]rules.Rule, 0, numRules)
+	for i := 0; i < numRules; i++ {
+		keywordRules = append(keywordRules, rules.KeywordRule(fmt.Sprintf("merchant %05d", i), categories[i%len(categories)]))
+	}
+	engine, err := rules.NewEngine(keywordRules)
+	if err != nil {
+		tb.Fatalf("failed to build engine: %v", err)
+	}
+
+	s := standard.BuildTestStatement(numTransactions)
+	transactions := make([]rules.Transaction, len(s))
+	for i := range s {
+		s[i].Description = fmt.Sprintf("SQ *MERCHANT %05d #%d RALEIGH NC", (i*7)%(2*numRules), i)
+		s[i].Category = ""
+		transactions[i] = s[i].RuleTransaction()
+	}
+	return engine, transactions
+}
+
+func TestMatchAgreesWithMatchLinear(t *testing.T) {
+	engine, transactions := buildMatchTest(t, 500, 1000)
+	for _, tr := range transactions {
+		indexed, indexedOK := engine.Match(tr)
+		linear, linearOK := engine.MatchLinear(tr)
+		if indexedOK != linearOK || indexed.Rule.Name != linear.Rule.Name || indexed.MatchedText != linear.MatchedText {
+			t.Errorf("indexed and linear matches of '%s' differ: %+v and %+v", tr.Description, indexed, linear)
+		}
+	}
+}
+
+func BenchmarkMatchIndexed(b *testing.B) {
+	engine, transactions := buildMatchTest(b, 1000, 2000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, t := range transactions {
+			engine.Match(t)
+		}
+	}
+}
+
+func BenchmarkMatchLinear(b *testing.B) {
+	engine, transactions := buildMatchTest(b, 1000, 2000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, t := range transactions {
+			engine.MatchLinear(t)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package rules
+
+// MatchLinear is Match without the index: it tries every rule in turn. The
+// tests check that it agrees with Match and benchmark the two.
+func (e *Engine) MatchLinear(t Transaction) (result Result, found bool) {
+	for _, r := range e.rules {
+		text, ok := r.matchedText(t)
+		if !ok {
+			continue
+		}
+		candidate := Result{Rule: r, MatchedText: text}
+		if !found || candidate.beats(result) {
+			result, found = candidate, true
+		}
+	}
+	return result, found
+}
@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// NoCategory is the expected category of a fixture that no rule should
+// match.
+const NoCategory = "none"
+
+// Fixture is a transaction with the category the rules should give it. In a
+// fixture file it is one line of the form
+//
+//	description, amount -> expected category
+//
+// where the amount follows the standard convention of money spent being
+// positive. The expected category is NoCategory when no rule should match
+// and SplitCategory when a split rule should. Blank lines and lines starting
+// with # are ignored.
+type Fixture struct {
+	FileName    string
+	Line        int
+	Description string
+	Amount      money.Amount
+	Category    string
+}
+
+func (f Fixture) String() string {
+	return fmt.Sprintf("%s:%d: '%s', %s", f.FileName, f.Line, f.Description, f.Amount)
+}
+
+func FixturesFromFile(fileName string) ([]Fixture, error) {
+	fixturesFileBytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var fixtures []Fixture
+	scanner := bufio.NewScanner(bytes.NewReader(fixturesFileBytes))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f, err := parseFixture(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		f.FileName, f.Line = fileName, lineNum
+		fixtures = append(fixtures, f)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan file: %w", err)
+	}
+	return fixtures, nil
+}
+
+// parseFixture splits at the last arrow and the last comma before it, so
+// descriptions may contain commas.
+func parseFixture(line string) (f Fixture, err error) {
+	transaction, category, ok := cutLast(line, "->")
+	if !ok {
+		return f, fmt.Errorf("'%s' has no '->' before the expected category", line)
+	}
+	description, amount, ok := cutLast(transaction, ",")
+	if !ok {
+		return f, fmt.Errorf("'%s' has no ',' before the amount", line)
+	}
+	f.Description = strings.TrimSpace(description)
+	f.Category = strings.TrimSpace(category)
+	if f.Description == "" || f.Category == "" {
+		return f, fmt.Errorf("'%s' needs a description and a category", line)
+	}
+	if f.Amount, err = money.Parse(strings.TrimSpace(amount)); err != nil {
+		return f, fmt.Errorf("failed to parse amount: %w", err)
+	}
+	return f, nil
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
+
+// Failure is a fixture the rules gave the wrong category. Got is NoCategory
+// when no rule matched.
+type Failure struct {
+	Fixture Fixture
+	Got     string
+	Rule    string
+}
+
+func (f Failure) String() string {
+	if f.Rule == "" {
+		return fmt.Sprintf("%s: expected %s, got %s", f.Fixture, f.Fixture.Category, f.Got)
+	}
+	return fmt.Sprintf("%s: expected %s, got %s from %s", f.Fixture, f.Fixture.Category, f.Got, f.Rule)
+}
+
+// Check runs each fixture through the engine the way the categorizer does,
+// with normalize, if not nil, cleaning the description first, and returns
+// the fixtures that got a different category.
+func (e *Engine) Check(fixtures []Fixture, normalize func(string) string) []Failure {
+	var failures []Failure
+	for _, f := range fixtures {
+		t := Transaction{Description: f.Description, Amount: f.Amount}
+		if normalize != nil {
+			t.Description, t.OriginalDescription = normalize(f.Description), f.Description
+		}
+		got, rule := NoCategory, ""
+		if result, ok := e.Match(t); ok {
+			got, rule = result.Category(), result.Rule.Name
+			if len(result.Rule.Splits) > 0 {
+				got = SplitCategory
+			}
+		}
+		if !strings.EqualFold(got, f.Category) {
+			failures = append(failures, Failure{Fixture: f, Got: got, Rule: rule})
+		}
+	}
+	return failures
+}
@@ -0,0 +1,137 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+)
+
+// Report is what Lint finds wrong with a set of rules.
+type Report struct {
+	// Conflicts are pairs of rules that overlap in a way that is probably a
+	// mistake.
+	Conflicts []string
+	// EmptyCategories are categories no rule files anything under.
+	EmptyCategories []string
+	// DeadRules matched none of the corpus transactions. It is empty when
+	// there is no corpus.
+	DeadRules []Rule
+	// NumTransactions is the size of the corpus and NumMatched how many of
+	// its transactions some rule matches.
+	NumTransactions int
+	NumMatched      int
+}
+
+// Coverage is the fraction of the corpus some rule matches.
+func (r Report) Coverage() float64 {
+	if r.NumTransactions == 0 {
+		return 0
+	}
+	return float64(r.NumMatched) / float64(r.NumTransactions)
+}
+
+func (r Report) NumProblems() int {
+	return len(r.Conflicts) + len(r.EmptyCategories) + len(r.DeadRules)
+}
+
+// Lint checks the engine's rules against each other, against categories and
+// against a corpus of past transactions, which may be empty.
+func Lint(e *Engine, categories category.List, corpus []Transaction) Report {
+	var report Report
+	report.Conflicts = conflicts(e.rules)
+	report.EmptyCategories = emptyCategories(e.rules, categories)
+
+	report.NumTransactions = len(corpus)
+	if len(corpus) == 0 {
+		return report
+	}
+	matched := make([]bool, len(e.rules))
+	for _, t := range corpus {
+		matchedAny := false
+		for i := range e.rules {
+			if _, ok := e.rules[i].matchedText(t); ok {
+				matched[i] = true
+				matchedAny = true
+			}
+		}
+		if matchedAny {
+			report.NumMatched++
+		}
+	}
+	for i, r := range e.rules {
+		if !matched[i] {
+			report.DeadRules = append(report.DeadRules, r)
+		}
+	}
+	return report
+}
+
+// conflicts finds rules whose patterns differ only by case, and substring
+// patterns of one category inside patterns of another. When both match, the
+// longer pattern wins unless the shorter one has a higher priority, so one of
+// the two categories never gets the descriptions the longer pattern covers.
+func conflicts(rules []Rule) []string {
+	var found []string
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			a, b := rules[i], rules[j]
+			if !isPlainText(a) || !isPlainText(b) {
+				continue
+			}
+			if a.Pattern != b.Pattern && strings.EqualFold(a.Pattern, b.Pattern) {
+				found = append(found, fmt.Sprintf("%s (%s) and %s (%s) differ only by case", a.Name, a.Category, b.Name, b.Category))
+				continue
+			}
+			if a.Category == b.Category {
+				continue
+			}
+			lowerA, lowerB := strings.ToLower(a.Pattern), strings.ToLower(b.Pattern)
+			switch {
+			case lowerA != lowerB && strings.Contains(lowerB, lowerA):
+				found = append(found, shadowing(a, b))
+			case lowerA != lowerB && strings.Contains(lowerA, lowerB):
+				found = append(found, shadowing(b, a))
+			case lowerA == lowerB:
+				found = append(found, fmt.Sprintf("%s (%s) and %s (%s) have the same pattern", a.Name, a.Category, b.Name, b.Category))
+			}
+		}
+	}
+	return found
+}
+
+func shadowing(short, long Rule) string {
+	if short.Priority > long.Priority {
+		return fmt.Sprintf("%s (%s) has a higher priority than %s (%s) and is part of it, so the longer rule never wins", short.Name, short.Category, long.Name, long.Category)
+	}
+	return fmt.Sprintf("%s (%s) is part of %s (%s), so descriptions containing '%s' go to %s", short.Name, short.Category, long.Name, long.Category, long.Pattern, long.Category)
+}
+
+// isPlainText reports whether r matches on its pattern's text alone, which
+// is when comparing patterns as strings says anything about the rules.
+func isPlainText(r Rule) bool {
+	if r.Pattern == "" || r.When != nil || len(r.Splits) > 0 {
+		return false
+	}
+	return r.Match == Contains || r.Match == Word
+}
+
+func emptyCategories(rules []Rule, categories category.List) []string {
+	used := map[string]bool{}
+	for _, r := range rules {
+		for _, c := range r.Categories() {
+			used[c] = true
+		}
+	}
+	var empty []string
+	for _, c := range categories {
+		// Transfers are found by pairing accounts, not by rules.
+		if c.Type == category.Transfer {
+			continue
+		}
+		if !used[c.Name] {
+			empty = append(empty, c.Name)
+		}
+	}
+	return empty
+}
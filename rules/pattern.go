@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MatchType says how a pattern is compared with a description. All match
+// types ignore case.
+type MatchType string
+
+const (
+	Contains MatchType = "contains"
+	Word     MatchType = "word"
+	Exact    MatchType = "exact"
+	Prefix   MatchType = "prefix"
+	Regex    MatchType = "regex"
+)
+
+// pattern is a compiled description pattern.
+type pattern struct {
+	text  string
+	match MatchType
+	re    *regexp.Regexp
+}
+
+func compilePattern(text string, match MatchType) (*pattern, error) {
+	if match == "" {
+		match = Contains
+	}
+	p := &pattern{text: text, match: match}
+
+	// Contains and prefix patterns are matched as regular expressions too,
+	// since lowercasing can change a description's length in bytes, so
+	// indexes into its lowercase form do not fit the description itself.
+	var expr string
+	switch match {
+	case Exact:
+		return p, nil
+	case Contains:
+		expr = regexp.QuoteMeta(text)
+	case Prefix:
+		expr = "^" + regexp.QuoteMeta(text)
+	case Word:
+		// \b only works next to word characters, so a pattern like "bp#"
+		// only needs a boundary at its start and one like "#123" only at
+		// its end.
+		expr = regexp.QuoteMeta(text)
+		if isWordByte(text[0]) {
+			expr = `\b` + expr
+		}
+		if isWordByte(text[len(text)-1]) {
+			expr += `\b`
+		}
+	case Regex:
+		expr = text
+	default:
+		return nil, fmt.Errorf("unknown match type '%s'", match)
+	}
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile pattern: %w", err)
+	}
+	p.re = re
+	return p, nil
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// matchedTextOf matches the pattern against t's description, falling back to
+// its original description.
+func (p *pattern) matchedTextOf(t Transaction) (string, bool) {
+	if text, ok := p.matchedText(t.Description); ok {
+		return text, true
+	}
+	if t.OriginalDescription == "" || t.OriginalDescription == t.Description {
+		return "", false
+	}
+	return p.matchedText(t.OriginalDescription)
+}
+
+// matchedText returns the part of description the pattern matched.
+func (p *pattern) matchedText(description string) (string, bool) {
+	if p.match == Exact {
+		if !strings.EqualFold(strings.TrimSpace(description), strings.TrimSpace(p.text)) {
+			return "", false
+		}
+		return description, true
+	}
+	loc := p.re.FindStringIndex(description)
+	if loc == nil {
+		return "", false
+	}
+	return description[loc[0]:loc[1]], true
+}
@@ -0,0 +1,40 @@
+package rules
+
+import "testing"
+
+func TestPatternMatchedText(t *testing.T) {
+	tests := []struct {
+		match       MatchType
+		pattern     string
+		description string
+		expected    string
+		expectedOK  bool
+	}{
+		{Contains, "café", "İSTANBUL CAFÉ", "CAFÉ", true},
+		{Contains, "café", "ȺȺ CAFÉ", "CAFÉ", true},
+		{Contains, "a.b", "xAXB a.B", "a.B", true},
+		{Contains, "teeter", "HARRIS TEETER 0120", "TEETER", true},
+		{Contains, "wegmans", "HARRIS TEETER 0120", "", false},
+		{Prefix, "ȺȺ c", "ȺȺ CAFÉ", "ȺȺ C", true},
+		{Prefix, "cafe", "SOCIETY CAFE", "", false},
+		{Exact, "dashi", " DASHI ", " DASHI ", true},
+		{Exact, "dash", "DASHI", "", false},
+		{Word, "bp", "BP#2300671S WILMST FAMIL", "BP", true},
+		{Word, "bp", "BPX STATION", "", false},
+		{Word, "bp#", "BP#2300671S WILMST FAMIL", "BP#", true},
+		{Word, "#85", "REI #85 DURHAM", "#85", true},
+		{Word, "*foo", "SQ *FOO BAR", "*FOO", true},
+		{Word, "*foo", "SQ *FOOD", "", false},
+		{Regex, `teeter \d+`, "HARRIS TEETER 0120", "TEETER 0120", true},
+	}
+	for _, test := range tests {
+		p, err := compilePattern(test.pattern, test.match)
+		if err != nil {
+			t.Fatalf("compilePattern(%q, %s) returned error: %v", test.pattern, test.match, err)
+		}
+		got, ok := p.matchedText(test.description)
+		if got != test.expected || ok != test.expectedOK {
+			t.Errorf("%s pattern %q on %q: expected %q, %v, got %q, %v", test.match, test.pattern, test.description, test.expected, test.expectedOK, got, ok)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Jack-Timothy/sheets-client/keywords"
+)
+
+// Rule files transactions matching it under Category, or divides them
+// between the categories of its Splits. A rule matches when its description
+// pattern, if any, matches and its When condition, if any, holds.
+type Rule struct {
+	// Name is shown to the user to explain why a transaction got its
+	// category. It defaults to a description of the pattern.
+	Name     string    `json:"name"`
+	Category string    `json:"category"`
+	Pattern  string    `json:"pattern"`
+	Match    MatchType `json:"match"`
+	// When holds any conditions beyond the description pattern.
+	When *Condition `json:"when"`
+	// Priority decides between rules that both match. Higher wins, and among
+	// equal priorities the rule matching the longest text wins.
+	Priority int `json:"priority"`
+	// Splits, if any, split matching transactions by fixed shares instead
+	// of filing them under Category, which may then be empty.
+	Splits []SplitLine `json:"splits"`
+
+	description *pattern
+}
+
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+func RulesFromFile(fileName string) ([]Rule, error) {
+	rulesFileHandle, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer rulesFileHandle.Close()
+
+	rulesFileBytes, err := io.ReadAll(rulesFileHandle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	var rf rulesFile
+	if err = json.Unmarshal(rulesFileBytes, &rf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	return rf.Rules, nil
+}
+
+// KeywordRule is the rule a keyword stands for.
+func KeywordRule(word, category string) Rule {
+	return Rule{
+		Name:     fmt.Sprintf("keyword '%s'", word),
+		Category: category,
+		Pattern:  word,
+		Match:    Contains,
+	}
+}
+
+// RulesFromKeywords turns every keyword into a contains rule with the default
+// priority. The rules are sorted by keyword so their order does not depend
+// on map iteration.
+func RulesFromKeywords(kwMap keywords.Map) []Rule {
+	words := make([]string, 0, len(kwMap))
+	for word := range kwMap {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	rules := make([]Rule, 0, len(words))
+	for _, word := range words {
+		rules = append(rules, KeywordRule(word, kwMap[word]))
+	}
+	return rules
+}
+
+func (r *Rule) compile() (err error) {
+	if r.Pattern == "" && r.When == nil {
+		return fmt.Errorf("rule '%s' has neither a pattern nor conditions", r.Name)
+	}
+	if r.Category == "" && len(r.Splits) == 0 {
+		return fmt.Errorf("rule '%s' has no category", r.Name)
+	}
+	if err = compileSplits(r.Splits); err != nil {
+		return fmt.Errorf("invalid splits of rule '%s': %w", r.Name, err)
+	}
+	if r.Match == "" {
+		r.Match = Contains
+	}
+	if r.Name == "" && r.Pattern != "" {
+		r.Name = fmt.Sprintf("%s '%s'", r.Match, r.Pattern)
+	}
+	if r.Name == "" && len(r.Splits) > 0 {
+		r.Name = fmt.Sprintf("conditions for a %d-way split", len(r.Splits))
+	}
+	if r.Name == "" {
+		r.Name = fmt.Sprintf("conditions for %s", r.Category)
+	}
+
+	if r.Pattern != "" {
+		if r.description, err = compilePattern(r.Pattern, r.Match); err != nil {
+			return fmt.Errorf("failed to compile pattern of rule '%s': %w", r.Name, err)
+		}
+	}
+	if r.When != nil {
+		if err = r.When.compile(); err != nil {
+			return fmt.Errorf("failed to compile conditions of rule '%s': %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// matchedText returns the part of the description the rule matched, which
+// is empty for rules without a pattern.
+func (r *Rule) matchedText(t Transaction) (string, bool) {
+	var text string
+	if r.description != nil {
+		var ok bool
+		if text, ok = r.description.matchedTextOf(t); !ok {
+			return "", false
+		}
+	}
+	if r.When != nil && !r.When.holds(t) {
+		return "", false
+	}
+	return text, true
+}
+
+// Categories returns every category r files transactions under.
+func (r Rule) Categories() []string {
+	var categories []string
+	if r.Category != "" {
+		categories = append(categories, r.Category)
+	}
+	for _, l := range r.Splits {
+		categories = append(categories, l.Category)
+	}
+	return categories
+}
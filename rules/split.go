@@ -0,0 +1,116 @@
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// SplitCategory is what a fixture expects of a transaction that a split
+// rule divides.
+const SplitCategory = "split"
+
+// SplitLine is one line of a split rule. A line takes either a fixed Amount,
+// with the sign of the transaction, or a Percent of the transaction's
+// amount. The Remainder line takes whatever the other lines leave, so
+// rounding never makes the lines miss the total; its own Amount and Percent
+// are ignored. If no line is marked, the last line is the remainder.
+type SplitLine struct {
+	Category    string        `json:"category"`
+	Description string        `json:"description"`
+	Percent     *float64      `json:"percent"`
+	Amount      *money.Amount `json:"amount"`
+	Remainder   bool          `json:"remainder"`
+}
+
+// Portion is the share of a transaction one split line gets.
+type Portion struct {
+	Category    string
+	Description string
+	Amount      money.Amount
+}
+
+func compileSplits(lines []SplitLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	if len(lines) == 1 {
+		return errors.New("a split needs at least two lines")
+	}
+	numRemainders := 0
+	totalPercent := 0.0
+	for i, l := range lines {
+		if l.Category == "" {
+			return fmt.Errorf("split line %d has no category", i+1)
+		}
+		if l.Remainder {
+			numRemainders++
+			continue
+		}
+		if l.Percent != nil && l.Amount != nil {
+			return fmt.Errorf("split line %d has both a percent and an amount", i+1)
+		}
+		if l.Percent != nil && (*l.Percent < 0 || *l.Percent > 100) {
+			return fmt.Errorf("split line %d has percent %v outside 0 to 100", i+1, *l.Percent)
+		}
+		if l.Percent != nil {
+			totalPercent += *l.Percent
+		}
+		if l.Percent == nil && l.Amount == nil && i != len(lines)-1 {
+			return fmt.Errorf("split line %d needs a percent, an amount or to be the remainder", i+1)
+		}
+	}
+	if numRemainders > 1 {
+		return errors.New("only one split line can be the remainder")
+	}
+	// The last line is only the remainder when no line is marked as it.
+	if last := lines[len(lines)-1]; numRemainders == 1 && !last.Remainder && last.Percent == nil && last.Amount == nil {
+		return fmt.Errorf("split line %d needs a percent or an amount since another line is the remainder", len(lines))
+	}
+	if totalPercent > 100 {
+		return fmt.Errorf("split lines add up to %v percent", totalPercent)
+	}
+	return nil
+}
+
+// SplitAmount divides amount between the rule's split lines. It returns nil
+// for rules that do not split, and an error when the lines' fixed amounts
+// add up to more than amount.
+func (r Rule) SplitAmount(amount money.Amount) ([]Portion, error) {
+	if len(r.Splits) == 0 {
+		return nil, nil
+	}
+	remainderIndex := len(r.Splits) - 1
+	for i, l := range r.Splits {
+		if l.Remainder {
+			remainderIndex = i
+		}
+	}
+
+	portions := make([]Portion, len(r.Splits))
+	remaining := amount
+	for i, l := range r.Splits {
+		portions[i] = Portion{Category: l.Category, Description: l.Description}
+		if i == remainderIndex {
+			continue
+		}
+		switch {
+		case l.Amount != nil:
+			portions[i].Amount = l.Amount.Abs()
+			if amount.Cents() < 0 {
+				portions[i].Amount = portions[i].Amount.Neg()
+			}
+		case l.Percent != nil:
+			cents := math.Round(float64(amount.Cents()) * *l.Percent / 100)
+			portions[i].Amount = money.FromCents(int64(cents))
+		}
+		remaining = remaining.Sub(portions[i].Amount)
+	}
+	if remaining.Cents() != 0 && (remaining.Cents() < 0) != (amount.Cents() < 0) {
+		return nil, fmt.Errorf("split lines of %s leave %s for the remainder", amount, remaining)
+	}
+	portions[remainderIndex].Amount = remaining
+	return portions, nil
+}
@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func percent(p float64) *float64 {
+	return &p
+}
+
+func amount(cents int64) *money.Amount {
+	a := money.FromCents(cents)
+	return &a
+}
+
+func TestCompileSplits(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []SplitLine
+		expectErr bool
+	}{
+		{"percent and last line", []SplitLine{{Category: "A", Percent: percent(60)}, {Category: "B"}}, false},
+		{"amount and marked remainder", []SplitLine{{Category: "A", Remainder: true}, {Category: "B", Amount: amount(500)}}, false},
+		{"one line", []SplitLine{{Category: "A"}}, true},
+		{"no category", []SplitLine{{Percent: percent(50)}, {Category: "B"}}, true},
+		{"percent and amount", []SplitLine{{Category: "A", Percent: percent(50), Amount: amount(100)}, {Category: "B"}}, true},
+		{"percent over 100", []SplitLine{{Category: "A", Percent: percent(150)}, {Category: "B"}}, true},
+		{"percents over 100", []SplitLine{{Category: "A", Percent: percent(60)}, {Category: "B", Percent: percent(60)}, {Category: "C"}}, true},
+		{"middle line without share", []SplitLine{{Category: "A"}, {Category: "B"}, {Category: "C"}}, true},
+		{"two remainders", []SplitLine{{Category: "A", Remainder: true}, {Category: "B", Remainder: true}}, true},
+		{"remainder and bare last line", []SplitLine{{Category: "A", Remainder: true}, {Category: "B"}}, true},
+	}
+	for _, test := range tests {
+		err := compileSplits(test.lines)
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+		}
+	}
+}
+
+func TestSplitAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []SplitLine
+		amount    int64
+		expected  []int64
+		expectErr bool
+	}{
+		{"percent", []SplitLine{{Category: "A", Percent: percent(60)}, {Category: "B"}}, 10000, []int64{6000, 4000}, false},
+		{"rounding goes to remainder", []SplitLine{{Category: "A", Percent: percent(33.3333)}, {Category: "B", Percent: percent(33.3333)}, {Category: "C"}}, 1000, []int64{333, 333, 334}, false},
+		{"marked remainder first", []SplitLine{{Category: "A", Remainder: true}, {Category: "B", Amount: amount(1500)}}, 5000, []int64{3500, 1500}, false},
+		{"refund keeps sign", []SplitLine{{Category: "A", Amount: amount(1500)}, {Category: "B"}}, -5000, []int64{-1500, -3500}, false},
+		{"amount equals total", []SplitLine{{Category: "A", Amount: amount(5000)}, {Category: "B"}}, 5000, []int64{5000, 0}, false},
+		{"amount over total", []SplitLine{{Category: "A", Amount: amount(6000)}, {Category: "B"}}, 5000, nil, true},
+		{"amount over refund total", []SplitLine{{Category: "A", Amount: amount(6000)}, {Category: "B"}}, -5000, nil, true},
+	}
+	for _, test := range tests {
+		if err := compileSplits(test.lines); err != nil {
+			t.Fatalf("%s: compileSplits returned error: %v", test.name, err)
+		}
+		r := Rule{Splits: test.lines}
+		portions, err := r.SplitAmount(money.FromCents(test.amount))
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+			continue
+		}
+		if len(portions) != len(test.expected) {
+			t.Errorf("%s: expected %d portions, got %d", test.name, len(test.expected), len(portions))
+			continue
+		}
+		for i, p := range portions {
+			if p.Amount.Cents() != test.expected[i] || p.Category != test.lines[i].Category {
+				t.Errorf("%s: portion %d: expected %s %d, got %s %d", test.name, i, test.lines[i].Category, test.expected[i], p.Category, p.Amount.Cents())
+			}
+		}
+	}
+}
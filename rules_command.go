@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/importer"
+	"github.com/Jack-Timothy/sheets-client/merchant"
+	"github.com/Jack-Timothy/sheets-client/rules"
+	"github.com/Jack-Timothy/sheets-client/rulestest"
+	"github.com/Jack-Timothy/sheets-client/sheet"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+const rulesUsage = `usage:
+  sheets-client rules lint [flags] [statement files...]
+  sheets-client rules test [flags] [fixture files...]`
+
+// rulesCommand runs the "rules" subcommands, which check the categorization
+// rules without importing anything into the sheet.
+func rulesCommand(args []string) {
+	if len(args) == 0 {
+		log.Fatal(rulesUsage)
+	}
+	switch args[0] {
+	case "lint":
+		rulesLint(args[1:])
+	case "test":
+		rulesTest(args[1:])
+	default:
+		log.Fatalf("Unknown rules command '%s'\n%s", args[0], rulesUsage)
+	}
+}
+
+// rulesLint reports conflicting rules, empty categories, and, given past
+// statements or history, the rules that never match and how much of the
+// history the rules cover. It exits with status 1 if it finds a problem.
+func rulesLint(args []string) {
+	flags := flag.NewFlagSet("rules lint", flag.ExitOnError)
+	rulesFileName := flags.String("rules", "rules.json", "JSON file of categorization rules")
+	keywordsFileName := flags.String("keywords", "keywords.json", "JSON file of keywords per category key")
+	categoriesFileName := flags.String("categories", "categories.json", "JSON file defining the categories")
+	aliasesFileName := flags.String("aliases", "aliases.json", "JSON file of merchant name aliases")
+	mappingsDirName := flags.String("mappings", "mappings", "directory of CSV column mapping files")
+	history := flags.String("history", noHistory, "past transactions to check the rules against as well as the statement files: 'sheet', a CSV ledger file, or 'none'")
+	spreadsheetID := flags.String("spreadsheet", "1dnKqyF20h90PT1ualeQHZJkJVH1LpnhZMRNH4-kwxws", "ID of the spreadsheet to read history from")
+	sheetName := flags.String("sheet", "Sheet1", "name of the sheet to read history from")
+	flags.Parse(args)
+
+	categories, err := category.ListFromFile(*categoriesFileName)
+	if err != nil {
+		log.Fatalf("Error loading categories: %v", err)
+	}
+	engine, err := rules.NewEngineFromFiles(*rulesFileName, *keywordsFileName, categories)
+	if err != nil {
+		log.Fatalf("Error loading categorization rules: %v", err)
+	}
+	normalizer, err := merchant.NormalizerFromFile(*aliasesFileName)
+	if err != nil {
+		log.Fatalf("Error loading merchant aliases: %v", err)
+	}
+	if err = registerImporters(*mappingsDirName, categories); err != nil {
+		log.Fatalf("Error registering importers: %v", err)
+	}
+
+	var corpus []rules.Transaction
+	for _, fileName := range flags.Args() {
+		s, _, err := importer.ImportFile(fileName)
+		if err != nil {
+			log.Fatalf("Error importing %s: %v", fileName, err)
+		}
+		s.NormalizeDescriptions(normalizer.Normalize)
+		for i := range s {
+			corpus = append(corpus, s[i].RuleTransaction())
+		}
+	}
+	var sheetRows standard.Statement
+	if *history == historyFromSheet {
+		sheetClient := sheet.NewClient(newSheetsService(), *spreadsheetID, *sheetName, categories)
+		if sheetRows, err = sheetClient.ReadStatement(); err != nil {
+			log.Fatalf("Error reading sheet history: %v", err)
+		}
+	}
+	examples, err := historyExamples(*history, sheetRows)
+	if err != nil {
+		log.Fatalf("Error reading history: %v", err)
+	}
+	for _, e := range examples {
+		t := standard.Transaction{Description: e.Description, Amount: e.Amount}
+		corpus = append(corpus, t.RuleTransaction())
+	}
+
+	report := rules.Lint(engine, categories, corpus)
+	printLintReport(report)
+	if report.NumProblems() > 0 {
+		os.Exit(1)
+	}
+}
+
+func printLintReport(report rules.Report) {
+	fmt.Printf("Conflicts: %d\n", len(report.Conflicts))
+	for _, c := range report.Conflicts {
+		fmt.Printf("  %s\n", c)
+	}
+	fmt.Printf("Empty categories: %d\n", len(report.EmptyCategories))
+	for _, c := range report.EmptyCategories {
+		fmt.Printf("  %s\n", c)
+	}
+	if report.NumTransactions == 0 {
+		fmt.Printf("No past transactions given, so dead rules and coverage were not checked.\n")
+		return
+	}
+	fmt.Printf("Dead rules: %d\n", len(report.DeadRules))
+	for _, r := range report.DeadRules {
+		fmt.Printf("  %s (%s)\n", r.Name, strings.Join(r.Categories(), ", "))
+	}
+	fmt.Printf("Coverage: %d of %d transactions (%.1f%%) match a rule.\n", report.NumMatched, report.NumTransactions, 100*report.Coverage())
+}
+
+// rulesTest runs the rule fixtures and prints the ones that fail. It exits
+// with status 1 if any fail.
+func rulesTest(args []string) {
+	flags := flag.NewFlagSet("rules test", flag.ExitOnError)
+	rulesFileName := flags.String("rules", "rules.json", "JSON file of categorization rules")
+	keywordsFileName := flags.String("keywords", "keywords.json", "JSON file of keywords per category key")
+	categoriesFileName := flags.String("categories", "categories.json", "JSON file defining the categories")
+	aliasesFileName := flags.String("aliases", "aliases.json", "JSON file of merchant name aliases")
+	fixturesDirName := flags.String("fixtures", "fixtures", "directory of fixture files to run when none are named")
+	flags.Parse(args)
+
+	fixtureFileNames := flags.Args()
+	if len(fixtureFileNames) == 0 {
+		var err error
+		if fixtureFileNames, err = filepath.Glob(filepath.Join(*fixturesDirName, "*.txt")); err != nil {
+			log.Fatalf("Error listing fixture files: %v", err)
+		}
+	}
+	failures, numFixtures, err := rulestest.Check(rulestest.Files{
+		Rules:      *rulesFileName,
+		Keywords:   *keywordsFileName,
+		Categories: *categoriesFileName,
+		Aliases:    *aliasesFileName,
+		Fixtures:   fixtureFileNames,
+	})
+	if err != nil {
+		log.Fatalf("Error running fixtures: %v", err)
+	}
+	for _, f := range failures {
+		fmt.Printf("FAIL %s\n", f)
+	}
+	fmt.Printf("%d of %d fixtures passed.\n", numFixtures-len(failures), numFixtures)
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,13 @@
+package rulestest
+
+import "testing"
+
+func TestRules(t *testing.T) {
+	Run(t, Files{
+		Rules:      "../rules.json",
+		Keywords:   "../keywords.json",
+		Categories: "../categories.json",
+		Aliases:    "../aliases.json",
+		Fixtures:   []string{"../fixtures/chase.txt"},
+	})
+}
@@ -0,0 +1,85 @@
+// Package rulestest runs rule fixtures, both for the "rules test" command and
+// from go test, as TestRules does for the repo's own configuration, so that a
+// keywords.json change that breaks a fixture fails like any other regression.
+package rulestest
+
+import (
+	"fmt"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/merchant"
+	"github.com/Jack-Timothy/sheets-client/rules"
+)
+
+// Files names the configuration to test. Rules and Aliases may be empty to
+// test without them.
+type Files struct {
+	Rules      string
+	Keywords   string
+	Categories string
+	Aliases    string
+	Fixtures   []string
+}
+
+// Check runs every fixture in files and returns the ones that failed along
+// with the number of fixtures run.
+func Check(files Files) (failures []rules.Failure, numFixtures int, err error) {
+	categories, err := category.ListFromFile(files.Categories)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load categories: %w", err)
+	}
+	engine, err := rules.NewEngineFromFiles(files.Rules, files.Keywords, categories)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load rules: %w", err)
+	}
+	var normalize func(string) string
+	if files.Aliases != "" {
+		normalizer, err := merchant.NormalizerFromFile(files.Aliases)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load merchant aliases: %w", err)
+		}
+		normalize = normalizer.Normalize
+	}
+
+	for _, fileName := range files.Fixtures {
+		fixtures, err := rules.FixturesFromFile(fileName)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load fixtures from %s: %w", fileName, err)
+		}
+		for _, f := range fixtures {
+			if f.Category == rules.NoCategory || f.Category == rules.SplitCategory {
+				continue
+			}
+			if _, ok := categories.ByName(f.Category); !ok {
+				return nil, 0, fmt.Errorf("fixture %s expects unknown category %s", f, f.Category)
+			}
+		}
+		numFixtures += len(fixtures)
+		failures = append(failures, engine.Check(fixtures, normalize)...)
+	}
+	return failures, numFixtures, nil
+}
+
+// TB is the part of testing.TB that Run uses. It keeps this package, which
+// the rules command also uses, from linking the testing package into the
+// binary.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// Run checks files and reports each failing fixture as a test error.
+func Run(tb TB, files Files) {
+	tb.Helper()
+	failures, numFixtures, err := Check(files)
+	if err != nil {
+		tb.Fatalf("failed to run rule fixtures: %v", err)
+	}
+	if numFixtures == 0 {
+		tb.Fatalf("no rule fixtures found in %v", files.Fixtures)
+	}
+	for _, f := range failures {
+		tb.Errorf("%s", f)
+	}
+}
@@ -0,0 +1,285 @@
+package sheet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/standard"
+	"google.golang.org/api/sheets/v4"
+)
+
+// The columns written for each transaction, in the order of
+// standard.Statement.GetRawData. The import ID column is hidden since it is
+// only there for spotting rows that were already imported. The split ID
+// column links the rows of a split transaction and the refund column links
+// refunds to the rows of their purchases. The last column is the account.
+// Any provenance columns follow it.
+const (
+	firstColumn            = "A"
+	lastColumn             = "H"
+	numColumns             = 8
+	categoryColumnIndex    = 1
+	descriptionColumnIndex = 2
+	importIDColumn         = "E"
+	importIDColumnIndex    = 4
+	splitIDColumn          = "F"
+	splitIDColumnIndex     = 5
+	refundOfColumnIndex    = 6
+	accountColumnIndex     = 7
+)
+
+// Client reads and appends statements on one sheet of a spreadsheet.
+type Client struct {
+	// ProvenanceColumns names the standard.ProvenanceFields written as
+	// extra columns after the account, and ProvenanceNote those written as
+	// a note on the description cell.
+	ProvenanceColumns []string
+	ProvenanceNote    []string
+
+	srv           *sheets.Service
+	spreadsheetID string
+	sheetName     string
+	categories    category.List
+}
+
+func NewClient(srv *sheets.Service, spreadsheetID, sheetName string, categories category.List) *Client {
+	return &Client{
+		srv:           srv,
+		spreadsheetID: spreadsheetID,
+		sheetName:     sheetName,
+		categories:    categories,
+	}
+}
+
+func (c *Client) rangeOf(fromColumn, toColumn string) string {
+	return fmt.Sprintf("'%s'!%s:%s", c.sheetName, fromColumn, toColumn)
+}
+
+// ExistingImportIDs returns the import IDs of every row already in the sheet,
+// along with the split IDs, which are the import IDs of split transactions.
+func (c *Client) ExistingImportIDs() (map[string]bool, error) {
+	resp, err := c.srv.Spreadsheets.Values.Get(c.spreadsheetID, c.rangeOf(importIDColumn, splitIDColumn)).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import ID columns: %w", err)
+	}
+	ids := map[string]bool{}
+	for _, row := range resp.Values {
+		for _, cell := range row {
+			if id, ok := cell.(string); ok && id != "" {
+				ids[id] = true
+			}
+		}
+	}
+	return ids, nil
+}
+
+// Append adds the transactions of s that are not in the sheet yet after the
+// last row of the sheet's table, a row per split line for split
+// transactions. Transactions whose import ID, or legacy import ID, is
+// already present are skipped, so appending the same statement twice is a
+// no-op.
+func (c *Client) Append(s standard.Statement) (numAppended, numSkipped int, err error) {
+	existingIDs, err := c.ExistingImportIDs()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get existing import IDs: %w", err)
+	}
+
+	newTransactions := make(standard.Statement, 0, len(s))
+	for _, t := range s {
+		if existingIDs[t.ImportID] || (t.LegacyImportID != "" && existingIDs[t.LegacyImportID]) {
+			numSkipped++
+			continue
+		}
+		newTransactions = append(newTransactions, t)
+	}
+	if len(newTransactions) == 0 {
+		return 0, numSkipped, nil
+	}
+
+	lines := newTransactions.Lines()
+	values := lines.GetRawData()
+	for i := range values {
+		for _, field := range c.ProvenanceColumns {
+			value, err := lines[i].Provenance.Field(field)
+			if err != nil {
+				return 0, numSkipped, fmt.Errorf("failed to get provenance of row %d: %w", i, err)
+			}
+			values[i] = append(values[i], value)
+		}
+	}
+	writeRange := c.rangeOf(firstColumn, columnName(numColumns+len(c.ProvenanceColumns)-1))
+	newValues := &sheets.ValueRange{
+		MajorDimension: "ROWS",
+		Range:          writeRange,
+		Values:         values,
+	}
+	resp, err := c.srv.Spreadsheets.Values.Append(c.spreadsheetID, writeRange, newValues).
+		ValueInputOption("USER_ENTERED").
+		InsertDataOption("INSERT_ROWS").
+		Do()
+	if err != nil {
+		return 0, numSkipped, fmt.Errorf("failed to append rows: %w", err)
+	}
+	firstRowIndex, err := firstRowIndexOf(resp.Updates.UpdatedRange)
+	if err != nil {
+		return len(newTransactions), numSkipped, fmt.Errorf("failed to find appended rows: %w", err)
+	}
+
+	if err = c.format(lines, firstRowIndex); err != nil {
+		return len(newTransactions), numSkipped, fmt.Errorf("failed to format sheet: %w", err)
+	}
+	return len(newTransactions), numSkipped, nil
+}
+
+// columnName returns the A1 name of the column with the zero-based index,
+// e.g. "A" for 0 and "AB" for 27.
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// firstRowIndexOf returns the zero-based index of the first row of an A1
+// range such as 'Sheet1'!A10:E12.
+func firstRowIndexOf(a1Range string) (int64, error) {
+	cells := a1Range[strings.LastIndex(a1Range, "!")+1:]
+	firstCell, _, _ := strings.Cut(cells, ":")
+	digits := strings.TrimLeft(firstCell, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	rowNum, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse row number of range %s: %w", a1Range, err)
+	}
+	return rowNum - 1, nil
+}
+
+func (c *Client) sheetID() (int64, error) {
+	spreadsheet, err := c.srv.Spreadsheets.Get(c.spreadsheetID).Fields("sheets.properties").Do()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get spreadsheet: %w", err)
+	}
+	for _, s := range spreadsheet.Sheets {
+		if s.Properties.Title == c.sheetName {
+			return s.Properties.SheetId, nil
+		}
+	}
+	return 0, fmt.Errorf("spreadsheet has no sheet named %s", c.sheetName)
+}
+
+// format hides the import ID column, limits the category column to the
+// configured categories, colors the category cells of the rows of lines,
+// which start at firstRowIndex, and adds any provenance notes.
+func (c *Client) format(lines standard.Statement, firstRowIndex int64) error {
+	sheetID, err := c.sheetID()
+	if err != nil {
+		return fmt.Errorf("failed to get sheet ID: %w", err)
+	}
+	requests := []*sheets.Request{
+		hideColumnRequest(sheetID, importIDColumnIndex),
+		c.categoryValidationRequest(sheetID),
+	}
+	for i, t := range lines {
+		cat, ok := c.categories.ByName(t.Category)
+		if !ok || cat.Color == "" {
+			continue
+		}
+		requests = append(requests, categoryColorRequest(sheetID, firstRowIndex+int64(i), cat))
+	}
+	if len(c.ProvenanceNote) > 0 {
+		for i, t := range lines {
+			if note := t.Provenance.Note(c.ProvenanceNote); note != "" {
+				requests = append(requests, noteRequest(sheetID, firstRowIndex+int64(i), descriptionColumnIndex, note))
+			}
+		}
+	}
+
+	req := &sheets.BatchUpdateSpreadsheetRequest{Requests: requests}
+	if _, err = c.srv.Spreadsheets.BatchUpdate(c.spreadsheetID, req).Do(); err != nil {
+		return fmt.Errorf("failed to batch update spreadsheet: %w", err)
+	}
+	return nil
+}
+
+func hideColumnRequest(sheetID, columnIndex int64) *sheets.Request {
+	return &sheets.Request{
+		UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+			Range: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  "COLUMNS",
+				StartIndex: columnIndex,
+				EndIndex:   columnIndex + 1,
+			},
+			Properties: &sheets.DimensionProperties{HiddenByUser: true},
+			Fields:     "hiddenByUser",
+		},
+	}
+}
+
+// categoryValidationRequest offers the category names as a dropdown in the
+// category column below the header row. Other values are still allowed so
+// that old rows with retired categories stay valid.
+func (c *Client) categoryValidationRequest(sheetID int64) *sheets.Request {
+	values := make([]*sheets.ConditionValue, 0, len(c.categories))
+	for _, name := range c.categories.Selectable().Names() {
+		values = append(values, &sheets.ConditionValue{UserEnteredValue: name})
+	}
+	return &sheets.Request{
+		SetDataValidation: &sheets.SetDataValidationRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    1,
+				StartColumnIndex: categoryColumnIndex,
+				EndColumnIndex:   categoryColumnIndex + 1,
+			},
+			Rule: &sheets.DataValidationRule{
+				Condition: &sheets.BooleanCondition{
+					Type:   "ONE_OF_LIST",
+					Values: values,
+				},
+				ShowCustomUi: true,
+			},
+		},
+	}
+}
+
+func categoryColorRequest(sheetID, rowIndex int64, c category.Category) *sheets.Request {
+	// Colors were validated when the categories were loaded.
+	r, g, b, _ := c.RGB()
+	return &sheets.Request{
+		RepeatCell: &sheets.RepeatCellRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    rowIndex,
+				EndRowIndex:      rowIndex + 1,
+				StartColumnIndex: categoryColumnIndex,
+				EndColumnIndex:   categoryColumnIndex + 1,
+			},
+			Cell: &sheets.CellData{
+				UserEnteredFormat: &sheets.CellFormat{
+					BackgroundColor: &sheets.Color{Red: r, Green: g, Blue: b},
+				},
+			},
+			Fields: "userEnteredFormat.backgroundColor",
+		},
+	}
+}
+
+func noteRequest(sheetID, rowIndex, columnIndex int64, note string) *sheets.Request {
+	return &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Start: &sheets.GridCoordinate{
+				SheetId:     sheetID,
+				RowIndex:    rowIndex,
+				ColumnIndex: columnIndex,
+			},
+			Rows: []*sheets.RowData{
+				{Values: []*sheets.CellData{{Note: note}}},
+			},
+			Fields: "note",
+		},
+	}
+}
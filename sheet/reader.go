@@ -0,0 +1,55 @@
+package sheet
+
+import (
+	"fmt"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+	"github.com/Jack-Timothy/sheets-client/standard"
+)
+
+// ReadStatement reads back every transaction row in the sheet. Rows that do
+// not hold a date and an amount, such as the header row, are skipped.
+func (c *Client) ReadStatement() (standard.Statement, error) {
+	resp, err := c.srv.Spreadsheets.Values.Get(c.spreadsheetID, c.rangeOf(firstColumn, lastColumn)).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet: %w", err)
+	}
+
+	s := make(standard.Statement, 0, len(resp.Values))
+	for _, row := range resp.Values {
+		t, ok := rowToTransaction(row)
+		if !ok {
+			continue
+		}
+		s = append(s, t)
+	}
+	return s, nil
+}
+
+func rowToTransaction(row []interface{}) (t standard.Transaction, ok bool) {
+	cell := func(index int) string {
+		if index >= len(row) {
+			return ""
+		}
+		return fmt.Sprint(row[index])
+	}
+	d, err := date.Parse(cell(0), append([]string{date.DisplayLayout}, date.InputLayouts...)...)
+	if err != nil {
+		return t, false
+	}
+	amount, err := money.Parse(cell(3))
+	if err != nil {
+		return t, false
+	}
+	return standard.Transaction{
+		Date:        d,
+		Category:    cell(1),
+		Description: cell(2),
+		Amount:      amount,
+		ImportID:    cell(importIDColumnIndex),
+		SplitID:     cell(splitIDColumnIndex),
+		RefundOf:    cell(refundOfColumnIndex),
+		Account:     cell(accountColumnIndex),
+	}, true
+}
@@ -0,0 +1,134 @@
+package standard
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/cleanprint"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// accountKey tells apart the accounts transactions come from: the
+// configured account if known, otherwise the importer that read them.
+func (t *Transaction) accountKey() string {
+	if t.Account != "" {
+		return t.Account
+	}
+	return t.Source
+}
+
+// sameAccount reports whether t and other may come from the same account,
+// which is when either account is unknown or they are equal.
+func sameAccount(t, other *Transaction) bool {
+	return t.Account == "" || other.Account == "" || t.Account == other.Account
+}
+
+// ResolveAccounts replaces the account of every transaction of s with the
+// name resolve gives for it. Importers set the account to the source's own
+// ID for it, e.g. an OFX ACCTID, or leave it empty, and resolve is called
+// once per distinct ID, so a file with several accounts keeps them apart.
+func (s Statement) ResolveAccounts(resolve func(sourceID string) (string, error)) error {
+	names := map[string]string{}
+	for i := range s {
+		sourceID := s[i].Account
+		name, ok := names[sourceID]
+		if !ok {
+			var err error
+			if name, err = resolve(sourceID); err != nil {
+				return fmt.Errorf("failed to resolve account '%s': %w", sourceID, err)
+			}
+			names[sourceID] = name
+		}
+		s[i].Account = name
+	}
+	return nil
+}
+
+// AccountNames returns the distinct accounts of s in the order they first
+// appear.
+func (s Statement) AccountNames() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, t := range s {
+		if !seen[t.Account] {
+			seen[t.Account] = true
+			names = append(names, t.Account)
+		}
+	}
+	return names
+}
+
+// ForAccounts returns the transactions of s from the named accounts.
+func (s Statement) ForAccounts(names ...string) Statement {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	filtered := make(Statement, 0, len(s))
+	for _, t := range s {
+		if wanted[t.Account] {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// GetAccountFromUser asks which of labels the transactions described by
+// source came from and returns its index, or -1 if the user leaves the
+// account unset.
+func GetAccountFromUser(source string, labels []string) (int, error) {
+	fmt.Printf("Which account are the transactions of %s from? Enter its enumeration, or press Enter to leave the account unset. Options are:\n", source)
+	for i, label := range labels {
+		fmt.Printf("%d. %s ", i+1, label)
+	}
+	fmt.Printf("\n")
+
+	input, err := getUserInput()
+	if err != nil {
+		return -1, fmt.Errorf("failed to get user input: %w", err)
+	}
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return -1, nil
+	}
+	accountEnum, err := strconv.Atoi(input)
+	if err != nil {
+		return -1, fmt.Errorf("failed to parse integer from user input: %w", err)
+	}
+	if accountEnum > len(labels) || accountEnum < 1 {
+		return -1, fmt.Errorf("received invalid account enumeration %d", accountEnum)
+	}
+	return accountEnum - 1, nil
+}
+
+// PrintAccountTotals prints, for each account in s, the money spent, the
+// money received and the net of the two.
+func (s Statement) PrintAccountTotals() {
+	spent := map[string]money.Amount{}
+	received := map[string]money.Amount{}
+	var names []string
+	for _, t := range s {
+		name := t.Account
+		if name == "" {
+			name = "(no account)"
+		}
+		if _, ok := spent[name]; !ok {
+			names = append(names, name)
+			spent[name] = 0
+		}
+		if t.Amount.Cents() > 0 {
+			spent[name] = spent[name].Add(t.Amount)
+		} else {
+			received[name] = received[name].Add(t.Amount.Neg())
+		}
+	}
+	sort.Strings(names)
+
+	lines := [][]string{{"Account", "Spent", "Received", "Net"}}
+	for _, name := range names {
+		lines = append(lines, []string{name, spent[name].String(), received[name].String(), spent[name].Sub(received[name]).String()})
+	}
+	cleanprint.Print(lines)
+}
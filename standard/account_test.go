@@ -0,0 +1,73 @@
+package standard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveAccounts(t *testing.T) {
+	s := Statement{{Account: "1234"}, {Account: "9876"}, {Account: "1234"}, {}}
+	calls := 0
+	err := s.ResolveAccounts(func(sourceID string) (string, error) {
+		calls++
+		if sourceID == "" {
+			return "Checking", nil
+		}
+		return "Card " + sourceID, nil
+	})
+	if err != nil {
+		t.Fatalf("ResolveAccounts returned error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected resolve to be called once per source ID, got %d calls", calls)
+	}
+	expected := []string{"Card 1234", "Card 9876", "Card 1234", "Checking"}
+	for i, name := range expected {
+		if s[i].Account != name {
+			t.Errorf("transaction %d: expected account %s, got %s", i, name, s[i].Account)
+		}
+	}
+	if got := strings.Join(s.AccountNames(), ","); got != "Card 1234,Card 9876,Checking" {
+		t.Errorf("AccountNames: expected Card 1234,Card 9876,Checking, got %s", got)
+	}
+	if got := s.ForAccounts("Card 1234", "Checking"); len(got) != 3 {
+		t.Errorf("ForAccounts: expected 3 transactions, got %d", len(got))
+	}
+
+	failing := Statement{{Account: "1234"}}
+	if err := failing.ResolveAccounts(func(string) (string, error) { return "", errors.New("unknown") }); err == nil {
+		t.Errorf("expected an error when resolve fails")
+	}
+}
+
+func TestAssignImportIDsWithAccount(t *testing.T) {
+	coffee := Transaction{Date: mustDate(t, "2024-01-05"), Description: "COFFEE", Amount: 450}
+	withoutAccount := Statement{coffee, coffee}
+	withoutAccount.AssignImportIDs("Bank")
+
+	onChecking := coffee
+	onChecking.Account = "Checking"
+	onSavings := coffee
+	onSavings.Account = "Savings"
+	withAccounts := Statement{onChecking, onChecking, onSavings}
+	withAccounts.AssignImportIDs("Bank")
+
+	if withAccounts[0].ImportID == withoutAccount[0].ImportID || withAccounts[0].ImportID == withAccounts[2].ImportID {
+		t.Errorf("expected the account to be part of the import ID")
+	}
+	if withAccounts[1].ImportID != withAccounts[0].ImportID+"-2" {
+		t.Errorf("expected counter suffix -2, got %s", withAccounts[1].ImportID)
+	}
+	// The legacy IDs are those the transactions had before accounts were
+	// part of import IDs, counted across accounts as they were then.
+	expectedLegacy := []string{withoutAccount[0].ImportID, withoutAccount[1].ImportID, withoutAccount[0].ImportID + "-3"}
+	for i, id := range expectedLegacy {
+		if withAccounts[i].LegacyImportID != id {
+			t.Errorf("transaction %d: expected legacy import ID %s, got %s", i, id, withAccounts[i].LegacyImportID)
+		}
+	}
+	if withoutAccount[0].LegacyImportID != "" {
+		t.Errorf("expected no legacy import ID without an account, got %s", withoutAccount[0].LegacyImportID)
+	}
+}
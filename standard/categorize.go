@@ -0,0 +1,261 @@
+package standard
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/classify"
+	"github.com/Jack-Timothy/sheets-client/keywords"
+	"github.com/Jack-Timothy/sheets-client/rules"
+)
+
+// numSuggestions is how many classifier suggestions the category prompt
+// offers.
+const numSuggestions = 3
+
+// Categorizer assigns categories to freshly imported transactions, asking the
+// user about any transaction no rule matches. Every importer's output goes
+// through the same Categorizer so that CSV, OFX and other sources are treated
+// alike.
+type Categorizer struct {
+	// Categories are the configured categories, which the user picks from.
+	Categories category.List
+	Rules      *rules.Engine
+	// Classifier, if set, suggests categories for transactions no rule
+	// matches and learns from the user's answers.
+	Classifier *classify.Classifier
+	// Keywords, if set, are compared with the descriptions no rule matches
+	// so that a near miss such as a typo or a truncated merchant name can be
+	// confirmed with one key. FuzzyThreshold is the similarity between 0
+	// and 1 a keyword needs to be offered.
+	Keywords       keywords.Map
+	FuzzyThreshold float64
+	// KeywordsFileName is where keywords the user asks to remember are
+	// saved. Leaving it empty turns off the offer to remember. Cities are
+	// left out of the keywords suggested, as merchant.Clean does.
+	KeywordsFileName string
+	Cities           []string
+	// RefundWindow, if positive, is how many days after a purchase a refund
+	// from the same merchant is matched to it, taking its category. History
+	// holds past purchases, e.g. the sheet's rows, to match refunds to
+	// along with the purchases being categorized.
+	RefundWindow int
+	History      Statement
+}
+
+// Categorize returns a copy of s with every transaction categorized and the
+// skipped transactions removed. Transactions that already have a category or
+// splits, e.g. from a QIF file, are kept as they are. Transactions are
+// categorized oldest first so that purchases are known before their
+// refunds, but keep their order in the returned statement.
+func (c *Categorizer) Categorize(s Statement) (Statement, error) {
+	var refunds *refundMatcher
+	if c.RefundWindow > 0 {
+		refunds = newRefundMatcher(c.RefundWindow, c.History)
+	}
+	s = append(Statement{}, s...)
+	order := make([]int, len(s))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		return s[order[x]].Date.Before(s[order[y]].Date)
+	})
+
+	skipped := make([]bool, len(s))
+	for _, i := range order {
+		t := &s[i]
+		if refunds != nil && matchRefund(refunds, t) {
+			skipped[i] = c.Categories.IsSkip(t.Category)
+			continue
+		}
+		skip, err := c.categorize(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to categorize item %d: %w", i, err)
+		}
+		skipped[i] = skip
+		if refunds != nil && !skip {
+			refunds.addPurchase(*t)
+		}
+	}
+
+	categorized := make(Statement, 0, len(s))
+	for i, t := range s {
+		if !skipped[i] {
+			categorized = append(categorized, t)
+		}
+	}
+	return categorized, nil
+}
+
+// matchRefund links an uncategorized refund to the purchase it returns
+// money for and gives it the purchase's category.
+func matchRefund(refunds *refundMatcher, t *Transaction) bool {
+	if t.Category != "" || len(t.Splits) > 0 {
+		return false
+	}
+	p, ok := refunds.match(*t)
+	if !ok {
+		return false
+	}
+	t.Category = p.category()
+	t.RefundOf = p.t.ImportID
+	refunds.addRefund(p.t.ImportID, t.Amount)
+	log.Printf("'%s' refunds the purchase of %s on %s, giving category %s.\n", t.Description, p.t.Amount, p.t.Date, t.Category)
+	return true
+}
+
+// offerToRemember asks whether to save a keyword for description so the
+// next transaction from the same merchant gets category without asking. It
+// does not ask when no keyword can be suggested for description.
+func (c *Categorizer) offerToRemember(description, category string) error {
+	if c.KeywordsFileName == "" {
+		return nil
+	}
+	suggestion := keywords.Suggest(description, c.Cities)
+	if suggestion == "" {
+		return nil
+	}
+	fmt.Printf("Remember this? Transactions containing the keyword '%s' will be filed under %s. Press Enter to accept, type a different keyword, or enter 'no'.\n", suggestion, category)
+	input, err := getUserInput()
+	if err != nil {
+		return fmt.Errorf("failed to get user input: %w", err)
+	}
+	word := suggestion
+	switch strings.TrimSpace(input) {
+	case "no":
+		return nil
+	case "":
+	default:
+		word = strings.ToLower(strings.TrimSpace(input))
+	}
+	if word == "" {
+		fmt.Printf("No keyword saved.\n\n")
+		return nil
+	}
+
+	if err = keywords.AddToFile(c.KeywordsFileName, c.Categories, category, word); err != nil {
+		return fmt.Errorf("failed to add keyword to %s: %w", c.KeywordsFileName, err)
+	}
+	if err = c.Rules.Add(rules.KeywordRule(word, category)); err != nil {
+		return fmt.Errorf("failed to add rule for keyword: %w", err)
+	}
+	if c.Keywords != nil {
+		c.Keywords[word] = category
+	}
+	fmt.Printf("Saved keyword '%s' for %s.\n\n", word, category)
+	return nil
+}
+
+// fuzzyMatch finds the keyword closest to t's description, trying the
+// description as the bank wrote it too.
+func (c *Categorizer) fuzzyMatch(t *Transaction) (keywords.FuzzyMatch, bool) {
+	if c.Keywords == nil || c.FuzzyThreshold <= 0 {
+		return keywords.FuzzyMatch{}, false
+	}
+	match, ok := c.Keywords.FuzzyMatch(t.Description, c.FuzzyThreshold)
+	if t.Provenance.OriginalDescription != "" {
+		original, originalOK := c.Keywords.FuzzyMatch(t.Provenance.OriginalDescription, c.FuzzyThreshold)
+		if originalOK && original.Similarity > match.Similarity {
+			match, ok = original, true
+		}
+	}
+	return match, ok
+}
+
+func confirmFuzzyMatch(match keywords.FuzzyMatch) (bool, error) {
+	fmt.Printf("Did you mean %s (via '%s')? Press Enter to accept or enter 'no' to choose yourself.\n", match.Category, match.Keyword)
+	input, err := getUserInput()
+	if err != nil {
+		return false, fmt.Errorf("failed to get user input: %w", err)
+	}
+	return strings.TrimSpace(input) != "no", nil
+}
+
+func splitsOf(portions []rules.Portion) []Split {
+	splits := make([]Split, 0, len(portions))
+	for _, p := range portions {
+		splits = append(splits, Split{Category: p.Category, Description: p.Description, Amount: p.Amount})
+	}
+	return splits
+}
+
+// RuleTransaction is what the rules see of t.
+func (t *Transaction) RuleTransaction() rules.Transaction {
+	return rules.Transaction{
+		Description:         t.Description,
+		OriginalDescription: t.Provenance.OriginalDescription,
+		Amount:              t.Amount,
+		Date:                t.Date,
+		SourceCategory:      t.Provenance.SourceCategory,
+		ItemType:            t.Provenance.ItemType,
+	}
+}
+
+func (c *Categorizer) categorize(t *Transaction) (skip bool, err error) {
+	if t.Category != "" || len(t.Splits) > 0 {
+		return false, nil
+	}
+
+	result, foundMatch := c.Rules.Match(t.RuleTransaction())
+	if foundMatch && len(result.Rule.Splits) > 0 {
+		portions, err := result.Rule.SplitAmount(t.Amount)
+		if err != nil {
+			return false, fmt.Errorf("failed to apply split rule %s: %w", result.Rule.Name, err)
+		}
+		if err = t.SetSplits(splitsOf(portions)); err != nil {
+			return false, fmt.Errorf("failed to apply split rule %s: %w", result.Rule.Name, err)
+		}
+		log.Printf("'%s' matched %s on '%s', splitting it %d ways.\n", t.Description, result.Rule.Name, result.MatchedText, len(t.Splits))
+	} else if foundMatch {
+		t.Category = result.Category()
+		skip = c.Categories.IsSkip(t.Category)
+		log.Printf("'%s' matched %s on '%s', giving category %s.\n", t.Description, result.Rule.Name, result.MatchedText, t.Category)
+	} else {
+		originalDescription := t.Description
+		t.printWithHeadings()
+		var accepted bool
+		if match, ok := c.fuzzyMatch(t); ok {
+			if accepted, err = confirmFuzzyMatch(match); err != nil {
+				return false, fmt.Errorf("failed to confirm fuzzy match: %w", err)
+			}
+			if accepted {
+				t.Category = match.Category
+				skip = c.Categories.IsSkip(t.Category)
+			}
+		}
+		if !accepted {
+			var suggestions []classify.Suggestion
+			if c.Classifier != nil {
+				suggestions = c.Classifier.Suggest(t.Description, t.Amount, numSuggestions)
+			}
+			skip, err = t.GetDescriptionAndCategoryFromUser(c.Categories, suggestions)
+			if err != nil {
+				return false, fmt.Errorf("failed to get description or category from user: %w", err)
+			}
+		}
+		if !skip {
+			// The user may have rewritten the description, but rules and
+			// suggestions see the one that was imported.
+			if c.Classifier != nil {
+				c.Classifier.Learn(originalDescription, t.Amount, t.Category)
+			}
+			// An accepted fuzzy match already has a keyword to remember.
+			if !accepted {
+				if err = c.offerToRemember(originalDescription, t.Category); err != nil {
+					log.Printf("Error remembering keyword: %v", err)
+				}
+			}
+		}
+	}
+	if skip {
+		fmt.Printf("Skipping transaction %+v.\n\n", *t)
+		return true, nil
+	}
+
+	log.Printf("Adding transaction %+v to statement.\n\n", *t)
+	return false, nil
+}
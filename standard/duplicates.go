@@ -0,0 +1,144 @@
+package standard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+)
+
+// nearDuplicateMaxDays is how far apart the dates of two transactions can be
+// for them to be suspected of being the same charge, e.g. a pending charge
+// and its posted version.
+const nearDuplicateMaxDays = 3
+
+// fingerprint is t's Fingerprint, or one made from its fields if it has
+// none, qualified by its account so that the same purchase on two cards is
+// not taken for a duplicate.
+func (t *Transaction) fingerprint() string {
+	fp := t.Fingerprint
+	if fp == "" {
+		fp = fmt.Sprintf("%s|%s|%s", t.Date.Format(date.ISOLayout), t.Amount.Decimal(), t.Description)
+	}
+	if t.Account != "" {
+		fp = t.Account + "|" + fp
+	}
+	return fp
+}
+
+// Merge combines statements from overlapping downloads into one, dropping
+// exact duplicates. A fingerprint that appears n times in one statement, e.g.
+// two identical coffees on the same day, is kept n times, and only copies
+// beyond the most any single statement has are dropped.
+func Merge(statements ...Statement) (merged Statement, numDropped int) {
+	maxCounts := map[string]int{}
+	for _, s := range statements {
+		counts := map[string]int{}
+		for i := range s {
+			counts[s[i].fingerprint()]++
+		}
+		for fp, count := range counts {
+			if count > maxCounts[fp] {
+				maxCounts[fp] = count
+			}
+		}
+	}
+
+	kept := map[string]int{}
+	for _, s := range statements {
+		for _, t := range s {
+			fp := t.fingerprint()
+			if kept[fp] >= maxCounts[fp] {
+				numDropped++
+				continue
+			}
+			kept[fp]++
+			merged = append(merged, t)
+		}
+	}
+	merged.sort()
+	return merged, numDropped
+}
+
+// duplicatePair holds the indexes of two transactions that may be the same
+// charge.
+type duplicatePair struct {
+	first, second int
+}
+
+// nearDuplicates finds pairs of transactions with the same amount, similar
+// descriptions and dates at most nearDuplicateMaxDays apart that were not
+// already dropped as exact duplicates.
+func (s Statement) nearDuplicates() []duplicatePair {
+	var pairs []duplicatePair
+	for i := range s {
+		for j := i + 1; j < len(s); j++ {
+			if s[i].isNearDuplicateOf(&s[j]) {
+				pairs = append(pairs, duplicatePair{first: i, second: j})
+			}
+		}
+	}
+	return pairs
+}
+
+func (t *Transaction) isNearDuplicateOf(other *Transaction) bool {
+	if t.Amount != other.Amount || !sameAccount(t, other) {
+		return false
+	}
+	days := t.Date.DaysUntil(other.Date)
+	if days < -nearDuplicateMaxDays || days > nearDuplicateMaxDays {
+		return false
+	}
+	// Identical rows of one statement on the same day are separate purchases
+	// that Merge deliberately kept.
+	if t.fingerprint() == other.fingerprint() {
+		return false
+	}
+	a := strings.ToLower(strings.TrimSpace(t.Description))
+	b := strings.ToLower(strings.TrimSpace(other.Description))
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+func pairKey(t, other *Transaction) string {
+	return t.ImportID + "|" + other.ImportID
+}
+
+// confirmNearDuplicates asks the user about each suspected duplicate pair
+// until none are left that the user has not already chosen to keep.
+func (s *Statement) confirmNearDuplicates() error {
+	kept := map[string]bool{}
+	for {
+		var pair *duplicatePair
+		for _, p := range s.nearDuplicates() {
+			if !kept[pairKey(&(*s)[p.first], &(*s)[p.second])] {
+				pair = &p
+				break
+			}
+		}
+		if pair == nil {
+			return nil
+		}
+
+		fmt.Println("These transactions look like the same charge:")
+		s.printIndexes(pair.first, pair.second)
+		fmt.Printf("Enter 'keep' to keep both, or the index (%d or %d) of the transaction to delete.\n", pair.first, pair.second)
+		input, err := getUserInput()
+		if err != nil {
+			return fmt.Errorf("failed to get user input: %w", err)
+		}
+		switch strings.TrimSpace(input) {
+		case "keep":
+			kept[pairKey(&(*s)[pair.first], &(*s)[pair.second])] = true
+		case fmt.Sprint(pair.first):
+			if err = s.deleteTransactionIndex(pair.first); err != nil {
+				return fmt.Errorf("failed to delete transaction with index %d: %w", pair.first, err)
+			}
+		case fmt.Sprint(pair.second):
+			if err = s.deleteTransactionIndex(pair.second); err != nil {
+				return fmt.Errorf("failed to delete transaction with index %d: %w", pair.second, err)
+			}
+		default:
+			fmt.Printf("'%s' is not a valid choice.\n\n", input)
+		}
+	}
+}
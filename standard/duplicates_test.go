@@ -0,0 +1,86 @@
+package standard
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func mustDate(t *testing.T, s string) date.Date {
+	t.Helper()
+	d, err := date.Parse(s, date.ISOLayout)
+	if err != nil {
+		t.Fatalf("failed to parse date %s: %v", s, err)
+	}
+	return d
+}
+
+func TestMerge(t *testing.T) {
+	coffee := Transaction{Date: mustDate(t, "2024-01-05"), Description: "COFFEE", Amount: 450}
+	lunch := Transaction{Date: mustDate(t, "2024-01-06"), Description: "LUNCH", Amount: 1200}
+	rent := Transaction{Date: mustDate(t, "2024-01-07"), Description: "RENT", Amount: 100000}
+	otherCard := coffee
+	otherCard.Account = "Visa"
+
+	tests := []struct {
+		name            string
+		statements      []Statement
+		expectedLength  int
+		expectedDropped int
+	}{
+		{"no overlap", []Statement{{coffee}, {lunch}}, 2, 0},
+		{"overlap", []Statement{{coffee, lunch}, {lunch, rent}}, 3, 1},
+		{"same file twice", []Statement{{coffee, lunch}, {coffee, lunch}}, 2, 2},
+		{"two coffees in one file", []Statement{{coffee, coffee}}, 2, 0},
+		{"two coffees overlapping one", []Statement{{coffee, coffee}, {coffee, lunch}}, 3, 1},
+		{"same purchase on another card", []Statement{{coffee}, {otherCard}}, 2, 0},
+	}
+	for _, test := range tests {
+		merged, dropped := Merge(test.statements...)
+		if len(merged) != test.expectedLength || dropped != test.expectedDropped {
+			t.Errorf("%s: expected %d transactions and %d dropped, got %d and %d", test.name, test.expectedLength, test.expectedDropped, len(merged), dropped)
+		}
+		for i := 1; i < len(merged); i++ {
+			if merged[i].Date.Before(merged[i-1].Date) {
+				t.Errorf("%s: merged statement is not sorted by date", test.name)
+			}
+		}
+	}
+}
+
+func TestMergeUsesImporterFingerprint(t *testing.T) {
+	pending := Transaction{Date: mustDate(t, "2024-01-05"), Description: "COFFEE", Amount: 450, Fingerprint: "bank|1"}
+	posted := Transaction{Date: mustDate(t, "2024-01-05"), Description: "COFFEE SHOP", Amount: 450, Fingerprint: "bank|1"}
+	merged, dropped := Merge(Statement{pending}, Statement{posted})
+	if len(merged) != 1 || dropped != 1 {
+		t.Errorf("expected 1 transaction and 1 dropped, got %d and %d", len(merged), dropped)
+	}
+}
+
+func TestIsNearDuplicateOf(t *testing.T) {
+	base := Transaction{Date: mustDate(t, "2024-01-05"), Description: "Coffee Shop", Amount: 450}
+	tests := []struct {
+		name     string
+		modify   func(tr, other *Transaction)
+		expected bool
+	}{
+		{"identical", func(tr, other *Transaction) {}, false},
+		{"posted two days later", func(tr, other *Transaction) { other.Date = mustDate(t, "2024-01-07") }, true},
+		{"posted a week later", func(tr, other *Transaction) { other.Date = mustDate(t, "2024-01-12") }, false},
+		{"longer description", func(tr, other *Transaction) { other.Description = "COFFEE SHOP #12" }, true},
+		{"different description", func(tr, other *Transaction) { other.Description = "Bakery" }, false},
+		{"different amount", func(tr, other *Transaction) { other.Amount = money.FromCents(451) }, false},
+		{"other account", func(tr, other *Transaction) {
+			tr.Account, other.Account, other.Description = "Amex", "Visa", "COFFEE SHOP #12"
+		}, false},
+		{"one account unknown", func(tr, other *Transaction) { other.Account, other.Date = "Visa", mustDate(t, "2024-01-06") }, true},
+	}
+	for _, test := range tests {
+		tr, other := base, base
+		test.modify(&tr, &other)
+		if got := tr.isNearDuplicateOf(&other); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
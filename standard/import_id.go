@@ -0,0 +1,59 @@
+package standard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+)
+
+// importIDLength is the number of hex characters of the hash kept in an
+// import ID. 16 characters make collisions between distinct transactions
+// practically impossible for a household ledger.
+const importIDLength = 16
+
+// AssignImportIDs gives every transaction in s that has no import ID yet one
+// derived from source, date, amount, description and, if set, account, and
+// sets its Source. Transactions with an account also get the LegacyImportID
+// they were given before accounts were part of import IDs.
+// Identical transactions, e.g. two coffees bought on the same day, are told
+// apart by a counter, so re-importing the same file yields the same IDs.
+func (s Statement) AssignImportIDs(source string) {
+	occurrences := map[string]int{}
+	legacyOccurrences := map[string]int{}
+	for i := range s {
+		t := &s[i]
+		if t.ImportID != "" {
+			continue
+		}
+		if t.Source == "" {
+			t.Source = source
+		}
+		t.ImportID = withCounter(t.importIDHash(t.Account), occurrences)
+		if t.Account != "" {
+			t.LegacyImportID = withCounter(t.importIDHash(""), legacyOccurrences)
+		}
+	}
+}
+
+// withCounter suffixes baseID with how many times it has been seen when that
+// is more than once.
+func withCounter(baseID string, occurrences map[string]int) string {
+	occurrences[baseID]++
+	if n := occurrences[baseID]; n > 1 {
+		return fmt.Sprintf("%s-%d", baseID, n)
+	}
+	return baseID
+}
+
+func (t *Transaction) importIDHash(account string) string {
+	key := fmt.Sprintf("%s|%s|%s|%s", t.Source, t.Date.Format(date.ISOLayout), t.Amount.Decimal(), t.Description)
+	// The account is only added when known so that transactions imported
+	// without one keep their IDs.
+	if account != "" {
+		key += "|" + account
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:importIDLength]
+}
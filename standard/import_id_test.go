@@ -0,0 +1,49 @@
+package standard
+
+import "testing"
+
+func TestAssignImportIDs(t *testing.T) {
+	coffee := Transaction{Date: mustDate(t, "2024-01-05"), Description: "COFFEE", Amount: 450}
+	lunch := Transaction{Date: mustDate(t, "2024-01-05"), Description: "LUNCH", Amount: 1200}
+	s := Statement{coffee, coffee, lunch, coffee}
+	s.AssignImportIDs("Bank")
+
+	base := s[0].ImportID
+	if len(base) != importIDLength {
+		t.Fatalf("expected an import ID of %d characters, got '%s'", importIDLength, base)
+	}
+	expected := []string{base, base + "-2", s[2].ImportID, base + "-3"}
+	for i, id := range expected {
+		if s[i].ImportID != id {
+			t.Errorf("transaction %d: expected import ID %s, got %s", i, id, s[i].ImportID)
+		}
+		if s[i].Source != "Bank" {
+			t.Errorf("transaction %d: expected source Bank, got %s", i, s[i].Source)
+		}
+	}
+	if s[2].ImportID == base {
+		t.Errorf("different transactions got the same import ID")
+	}
+
+	again := Statement{coffee, coffee, lunch, coffee}
+	again.AssignImportIDs("Bank")
+	for i := range s {
+		if again[i].ImportID != s[i].ImportID {
+			t.Errorf("transaction %d: re-importing gave import ID %s instead of %s", i, again[i].ImportID, s[i].ImportID)
+		}
+	}
+
+	other := Statement{coffee}
+	other.AssignImportIDs("Other bank")
+	if other[0].ImportID == base {
+		t.Errorf("the same transaction from another source got the same import ID")
+	}
+}
+
+func TestAssignImportIDsKeepsExisting(t *testing.T) {
+	s := Statement{{Date: mustDate(t, "2024-01-05"), Description: "COFFEE", Amount: 450, ImportID: "kept", Source: "Sheet"}}
+	s.AssignImportIDs("Bank")
+	if s[0].ImportID != "kept" || s[0].Source != "Sheet" {
+		t.Errorf("expected import ID kept from Sheet, got %s from %s", s[0].ImportID, s[0].Source)
+	}
+}
@@ -0,0 +1,83 @@
+package standard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/date"
+)
+
+// Provenance records where a transaction came from, for auditing and for
+// rules. Importers fill in what their format has and leave the rest empty.
+type Provenance struct {
+	SourceFile string
+	// Row is the 1-based row, line or record of the transaction in
+	// SourceFile, or 0 if unknown.
+	Row int
+	// OriginalDescription is the description as the bank wrote it, before
+	// merchant normalization.
+	OriginalDescription string
+	PostedDate          date.Date
+	// SourceCategory and ItemType are the category and type the bank gave
+	// the transaction, e.g. Chase's "Groceries" and "Sale".
+	SourceCategory string
+	ItemType       string
+	Memo           string
+}
+
+// ProvenanceFields are the names by which provenance fields can be chosen to
+// be written to the sheet.
+var ProvenanceFields = []string{
+	"source_file", "row", "original_description", "posted_date", "source_category", "type", "memo",
+}
+
+// ValidateProvenanceFields checks that every name is one of
+// ProvenanceFields.
+func ValidateProvenanceFields(names []string) error {
+	for _, name := range names {
+		if _, err := (Provenance{}).Field(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Field returns the value of the field with the given name as text, empty
+// if it is not known.
+func (p Provenance) Field(name string) (string, error) {
+	switch name {
+	case "source_file":
+		return p.SourceFile, nil
+	case "row":
+		if p.Row == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(p.Row), nil
+	case "original_description":
+		return p.OriginalDescription, nil
+	case "posted_date":
+		if p.PostedDate.IsZero() {
+			return "", nil
+		}
+		return p.PostedDate.String(), nil
+	case "source_category":
+		return p.SourceCategory, nil
+	case "type":
+		return p.ItemType, nil
+	case "memo":
+		return p.Memo, nil
+	}
+	return "", fmt.Errorf("unknown provenance field '%s', expected one of %s", name, strings.Join(ProvenanceFields, ", "))
+}
+
+// Note lists the named fields that are known, one "name: value" per line.
+func (p Provenance) Note(names []string) string {
+	var lines []string
+	for _, name := range names {
+		if value, err := p.Field(name); err == nil && value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,151 @@
+package standard
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/cleanprint"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// DefaultRefundWindow is how many days after a purchase a refund of it is
+// looked for.
+const DefaultRefundWindow = 90
+
+// refundMatcher finds the purchases that refunds give money back for.
+type refundMatcher struct {
+	window int
+	// purchases are the candidate purchases by lowercase description.
+	purchases  map[string][]*purchase
+	byImportID map[string]*purchase
+}
+
+type purchase struct {
+	t        Transaction
+	refunded money.Amount
+}
+
+func newRefundMatcher(window int, history Statement) *refundMatcher {
+	m := &refundMatcher{
+		window:     window,
+		purchases:  map[string][]*purchase{},
+		byImportID: map[string]*purchase{},
+	}
+	for _, t := range history {
+		m.addPurchase(t)
+	}
+	for _, t := range history {
+		if t.RefundOf != "" {
+			m.addRefund(t.RefundOf, t.Amount)
+		}
+	}
+	return m
+}
+
+// addPurchase makes t a candidate for later refunds if it is a categorized
+// purchase that can be linked to.
+func (m *refundMatcher) addPurchase(t Transaction) {
+	if t.Amount.Cents() <= 0 || t.ImportID == "" || (t.Category == "" && len(t.Splits) == 0) {
+		return
+	}
+	p := &purchase{t: t}
+	key := strings.ToLower(t.Description)
+	m.purchases[key] = append(m.purchases[key], p)
+	m.byImportID[t.ImportID] = p
+}
+
+// addRefund records that amount of the purchase with importID was refunded.
+func (m *refundMatcher) addRefund(importID string, amount money.Amount) {
+	if p, ok := m.byImportID[importID]; ok {
+		p.refunded = p.refunded.Add(amount.Neg())
+	}
+}
+
+// match finds the purchase refund gives money back for: one from the same
+// merchant in the window before it with at least the refund's amount left
+// to refund, made from the same account if both accounts are known. A
+// purchase of exactly the refunded amount beats a partial
+// match, and otherwise the latest purchase wins.
+func (m *refundMatcher) match(refund Transaction) (*purchase, bool) {
+	if refund.Amount.Cents() >= 0 {
+		return nil, false
+	}
+	amount := refund.Amount.Neg()
+	var best *purchase
+	for _, p := range m.purchases[strings.ToLower(refund.Description)] {
+		daysBefore := p.t.Date.DaysUntil(refund.Date)
+		if daysBefore < 0 || daysBefore > m.window {
+			continue
+		}
+		if p.t.Amount.Sub(p.refunded).Cents() < amount.Cents() || !sameAccount(&p.t, &refund) {
+			continue
+		}
+		if best == nil || p.beats(best, amount) {
+			best = p
+		}
+	}
+	return best, best != nil
+}
+
+func (p *purchase) beats(other *purchase, refundAmount money.Amount) bool {
+	exact, otherExact := p.t.Amount == refundAmount, other.t.Amount == refundAmount
+	if exact != otherExact {
+		return exact
+	}
+	return p.t.Date.After(other.t.Date)
+}
+
+// category is the category a refund of p is filed under: the purchase's
+// own, or that of its largest split line.
+func (p *purchase) category() string {
+	if len(p.t.Splits) == 0 {
+		return p.t.Category
+	}
+	largest := p.t.Splits[0]
+	for _, split := range p.t.Splits[1:] {
+		if split.Amount.Cents() > largest.Amount.Cents() {
+			largest = split
+		}
+	}
+	return largest.Category
+}
+
+// CategoryTotals adds up the amounts of s by category, split lines counting
+// toward their own categories. Refunds carry the category of what they
+// refund, so the totals are net of returns.
+func (s Statement) CategoryTotals() map[string]money.Amount {
+	totals := map[string]money.Amount{}
+	for _, t := range s.Lines() {
+		totals[t.Category] = totals[t.Category].Add(t.Amount)
+	}
+	return totals
+}
+
+// PrintCategoryTotals prints the net total of every category in s, in the
+// order of categories.
+func (s Statement) PrintCategoryTotals(categories category.List) {
+	totals := s.CategoryTotals()
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	order := map[string]int{}
+	for i, c := range categories {
+		order[c.Name] = i + 1
+	}
+	sort.SliceStable(names, func(x, y int) bool {
+		ox, oy := order[names[x]], order[names[y]]
+		if ox != oy {
+			// Categories that are not configured go last.
+			return ox != 0 && (oy == 0 || ox < oy)
+		}
+		return names[x] < names[y]
+	})
+
+	lines := [][]string{{"Category", "Net Total"}}
+	for _, name := range names {
+		lines = append(lines, []string{name, totals[name].String()})
+	}
+	cleanprint.Print(lines)
+}
@@ -0,0 +1,90 @@
+package standard
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func TestRefundMatcher(t *testing.T) {
+	shoes := Transaction{Date: mustDate(t, "2024-01-05"), Description: "SHOE STORE", Amount: 8000, Category: "Clothes", ImportID: "shoes"}
+	socks := Transaction{Date: mustDate(t, "2024-01-20"), Description: "Shoe Store", Amount: 1000, Category: "Clothes", ImportID: "socks"}
+	uncategorized := Transaction{Date: mustDate(t, "2024-01-06"), Description: "SHOE STORE", Amount: 9000, ImportID: "unknown"}
+	split := Transaction{Date: mustDate(t, "2024-01-07"), Description: "TARGET", Amount: 5000, ImportID: "target", Splits: []Split{
+		{Category: "Groceries", Amount: 1500},
+		{Category: "Home", Amount: 3500},
+	}}
+	history := Statement{shoes, socks, uncategorized, split}
+
+	tests := []struct {
+		name             string
+		refund           Transaction
+		expectedImportID string
+		expectedCategory string
+	}{
+		{"exact amount beats later purchase", Transaction{Date: mustDate(t, "2024-02-01"), Description: "shoe store", Amount: -8000}, "shoes", "Clothes"},
+		{"partial refund of the latest purchase", Transaction{Date: mustDate(t, "2024-02-01"), Description: "SHOE STORE", Amount: -500}, "socks", "Clothes"},
+		{"more than any purchase", Transaction{Date: mustDate(t, "2024-02-01"), Description: "SHOE STORE", Amount: -9000}, "", ""},
+		{"outside the window", Transaction{Date: mustDate(t, "2024-06-01"), Description: "SHOE STORE", Amount: -8000}, "", ""},
+		{"before the purchase", Transaction{Date: mustDate(t, "2024-01-01"), Description: "SHOE STORE", Amount: -8000}, "", ""},
+		{"other merchant", Transaction{Date: mustDate(t, "2024-02-01"), Description: "BOOK STORE", Amount: -8000}, "", ""},
+		{"not a refund", Transaction{Date: mustDate(t, "2024-02-01"), Description: "SHOE STORE", Amount: 8000}, "", ""},
+		{"largest split line", Transaction{Date: mustDate(t, "2024-02-01"), Description: "TARGET", Amount: -1500}, "target", "Home"},
+	}
+	for _, test := range tests {
+		m := newRefundMatcher(DefaultRefundWindow, history)
+		p, ok := m.match(test.refund)
+		if !ok {
+			if test.expectedImportID != "" {
+				t.Errorf("%s: expected a match with %s, got none", test.name, test.expectedImportID)
+			}
+			continue
+		}
+		if p.t.ImportID != test.expectedImportID || p.category() != test.expectedCategory {
+			t.Errorf("%s: expected %s in %s, got %s in %s", test.name, test.expectedImportID, test.expectedCategory, p.t.ImportID, p.category())
+		}
+	}
+}
+
+func TestRefundMatcherCountsEarlierRefunds(t *testing.T) {
+	shoes := Transaction{Date: mustDate(t, "2024-01-05"), Description: "SHOE STORE", Amount: 8000, Category: "Clothes", ImportID: "shoes"}
+	earlier := Transaction{Date: mustDate(t, "2024-01-10"), Description: "SHOE STORE", Amount: -6000, Category: "Clothes", ImportID: "earlier", RefundOf: "shoes"}
+	m := newRefundMatcher(DefaultRefundWindow, Statement{shoes, earlier})
+
+	refund := Transaction{Date: mustDate(t, "2024-01-15"), Description: "SHOE STORE", Amount: -3000}
+	if _, ok := m.match(refund); ok {
+		t.Errorf("expected no match for more than the $20.00 left to refund")
+	}
+	refund.Amount = money.FromCents(-2000)
+	p, ok := m.match(refund)
+	if !ok || p.t.ImportID != "shoes" {
+		t.Fatalf("expected a match with shoes")
+	}
+	m.addRefund(p.t.ImportID, refund.Amount)
+	refund.Amount = money.FromCents(-1)
+	if _, ok := m.match(refund); ok {
+		t.Errorf("expected no match once the purchase is fully refunded")
+	}
+}
+
+func TestCategoryTotals(t *testing.T) {
+	s := Statement{
+		{Category: "Clothes", Amount: 8000},
+		{Category: "Clothes", Amount: -3000, RefundOf: "shoes"},
+		{Category: "Food", Amount: 450},
+		{Amount: 5000, ImportID: "target", Splits: []Split{
+			{Category: "Food", Amount: 1500},
+			{Category: "Home", Amount: 3500},
+		}},
+	}
+	expected := map[string]money.Amount{"Clothes": 5000, "Food": 1950, "Home": 3500}
+	totals := s.CategoryTotals()
+	if len(totals) != len(expected) {
+		t.Errorf("expected %d categories, got %v", len(expected), totals)
+	}
+	for name, amount := range expected {
+		if totals[name] != amount {
+			t.Errorf("%s: expected %s, got %s", name, amount, totals[name])
+		}
+	}
+}
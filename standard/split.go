@@ -0,0 +1,156 @@
+package standard
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+// Split is one line of a transaction divided between several categories,
+// e.g. the groceries and the gifts on one Target receipt. Description may
+// be empty to use the parent's.
+type Split struct {
+	Category    string
+	Description string
+	Amount      money.Amount
+}
+
+// SetSplits divides t into splits, whose amounts must add up to t's amount.
+// Passing no splits undoes a previous split.
+func (t *Transaction) SetSplits(splits []Split) error {
+	if len(splits) == 0 {
+		t.Splits = nil
+		return nil
+	}
+	if len(splits) == 1 {
+		return errors.New("a split needs at least two lines")
+	}
+	amounts := make([]money.Amount, 0, len(splits))
+	for i, split := range splits {
+		if split.Category == "" {
+			return fmt.Errorf("split line %d has no category", i+1)
+		}
+		amounts = append(amounts, split.Amount)
+	}
+	if total := money.Sum(amounts...); total != t.Amount {
+		return fmt.Errorf("split lines add up to %s, not the transaction's %s", total, t.Amount)
+	}
+	t.Splits = append([]Split{}, splits...)
+	return nil
+}
+
+// Lines returns s with every split transaction replaced by one transaction
+// per split line, as they are written to the sheet. The lines share the
+// parent's import ID as their SplitID and get import IDs of their own made
+// from it.
+func (s Statement) Lines() Statement {
+	lines := make(Statement, 0, len(s))
+	for _, t := range s {
+		if len(t.Splits) == 0 {
+			lines = append(lines, t)
+			continue
+		}
+		for i, split := range t.Splits {
+			line := t
+			line.Splits = nil
+			line.Category = split.Category
+			line.Amount = split.Amount
+			if split.Description != "" {
+				line.Description = split.Description
+			}
+			line.SplitID = t.ImportID
+			line.ImportID = fmt.Sprintf("%s/%d", t.ImportID, i+1)
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func (s *Statement) handleUserSplittingTransaction(input string, categories category.List) error {
+	input = strings.TrimPrefix(input, "split")
+	input = strings.TrimSpace(input)
+	indexToSplit, err := strconv.ParseUint(input, 10, bitsPerWord)
+	if err != nil {
+		return fmt.Errorf("failed to parse unsigned integer from user input: %w", err)
+	}
+	tr, err := s.getTransactionWithIndex(int(indexToSplit))
+	if err != nil {
+		return fmt.Errorf("failed to get transaction with index %d: %w", indexToSplit, err)
+	}
+
+	fmt.Println("Splitting the following transaction:")
+	tr.printWithHeadings()
+	splits, err := tr.getSplitsFromUser(categories)
+	if err != nil {
+		return fmt.Errorf("failed to get split lines from user: %w", err)
+	}
+	if err = tr.SetSplits(splits); err != nil {
+		return fmt.Errorf("failed to split transaction: %w", err)
+	}
+
+	fmt.Println("Resulting transaction data after split:")
+	tr.printWithHeadings()
+	return nil
+}
+
+// getSplitsFromUser asks for split lines until they add up to t's amount.
+// From the second line on, the user can put the remaining amount in a line
+// by pressing Enter.
+func (t *Transaction) getSplitsFromUser(categories category.List) ([]Split, error) {
+	var splits []Split
+	remaining := t.Amount
+	for remaining != 0 || len(splits) == 0 {
+		if len(splits) == 0 {
+			fmt.Printf("Enter the amount of split line 1 out of %s.\n", t.Amount)
+		} else {
+			fmt.Printf("Enter the amount of split line %d, or press Enter to put the remaining %s in it.\n", len(splits)+1, remaining)
+		}
+		amountInput, err := getUserInput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user input: %w", err)
+		}
+		amount := remaining
+		if strings.TrimSpace(amountInput) != "" {
+			if amount, err = money.Parse(amountInput); err != nil {
+				return nil, fmt.Errorf("failed to parse amount: %w", err)
+			}
+		}
+		// The remainder of the first line is the whole amount, which would
+		// leave nothing to split.
+		if len(splits) == 0 && amount == t.Amount {
+			fmt.Printf("The first split line needs an amount other than the whole %s.\n", t.Amount)
+			continue
+		}
+		if !fitsIn(amount, remaining) {
+			fmt.Printf("A split line of %s does not fit in the remaining %s.\n", amount, remaining)
+			continue
+		}
+
+		line := Transaction{Description: t.Description}
+		if err = line.getCategoryFromUser(categories); err != nil {
+			return nil, fmt.Errorf("failed to get category from user: %w", err)
+		}
+		fmt.Printf("Enter a description of split line %d, or press Enter to use '%s'.\n", len(splits)+1, t.Description)
+		description, err := getUserInput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user input: %w", err)
+		}
+
+		splits = append(splits, Split{Category: line.Category, Description: strings.TrimSpace(description), Amount: amount})
+		remaining = remaining.Sub(amount)
+	}
+	return splits, nil
+}
+
+// fitsIn reports whether a split line of amount leaves a remainder with the
+// sign of remaining, so that no line goes the wrong way.
+func fitsIn(amount, remaining money.Amount) bool {
+	if remaining.Cents() < 0 {
+		return amount.Cents() <= 0 && amount.Cents() >= remaining.Cents()
+	}
+	return amount.Cents() >= 0 && amount.Cents() <= remaining.Cents()
+}
@@ -0,0 +1,86 @@
+package standard
+
+import (
+	"testing"
+
+	"github.com/Jack-Timothy/sheets-client/money"
+)
+
+func TestSetSplits(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int64
+		splits    []Split
+		expectErr bool
+	}{
+		{"two lines", 5000, []Split{{Category: "A", Amount: 3000}, {Category: "B", Amount: 2000}}, false},
+		{"refund", -5000, []Split{{Category: "A", Amount: -3000}, {Category: "B", Amount: -2000}}, false},
+		{"undo", 5000, nil, false},
+		{"one line", 5000, []Split{{Category: "A", Amount: 5000}}, true},
+		{"wrong total", 5000, []Split{{Category: "A", Amount: 3000}, {Category: "B", Amount: 1000}}, true},
+		{"no category", 5000, []Split{{Category: "A", Amount: 3000}, {Amount: 2000}}, true},
+	}
+	for _, test := range tests {
+		tr := Transaction{Amount: money.FromCents(test.amount), Splits: []Split{{Category: "Old"}}}
+		err := tr.SetSplits(test.splits)
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectErr, err)
+			continue
+		}
+		if !test.expectErr && len(tr.Splits) != len(test.splits) {
+			t.Errorf("%s: expected %d split lines, got %d", test.name, len(test.splits), len(tr.Splits))
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	s := Statement{
+		{Description: "COFFEE", Amount: 500, Category: "Food", ImportID: "a"},
+		{Description: "TARGET", Amount: 5000, ImportID: "b", Splits: []Split{
+			{Category: "Groceries", Amount: 3000},
+			{Category: "Gifts", Description: "Birthday card", Amount: 2000},
+		}},
+	}
+	expected := []struct {
+		description, category, importID, splitID string
+		amount                                   money.Amount
+	}{
+		{"COFFEE", "Food", "a", "", 500},
+		{"TARGET", "Groceries", "b/1", "b", 3000},
+		{"Birthday card", "Gifts", "b/2", "b", 2000},
+	}
+	lines := s.Lines()
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d", len(expected), len(lines))
+	}
+	for i, e := range expected {
+		l := lines[i]
+		if l.Description != e.description || l.Category != e.category || l.ImportID != e.importID || l.SplitID != e.splitID || l.Amount != e.amount || len(l.Splits) != 0 {
+			t.Errorf("line %d: expected %+v, got %+v", i, e, l)
+		}
+	}
+	if len(s[1].Splits) != 2 {
+		t.Errorf("Lines changed the statement's split lines")
+	}
+}
+
+func TestFitsIn(t *testing.T) {
+	tests := []struct {
+		amount, remaining int64
+		expected          bool
+	}{
+		{3000, 5000, true},
+		{5000, 5000, true},
+		{0, 5000, true},
+		{6000, 5000, false},
+		{-1000, 5000, false},
+		{-3000, -5000, true},
+		{-6000, -5000, false},
+		{1000, -5000, false},
+	}
+	for _, test := range tests {
+		if got := fitsIn(money.FromCents(test.amount), money.FromCents(test.remaining)); got != test.expected {
+			t.Errorf("fitsIn(%d, %d): expected %v, got %v", test.amount, test.remaining, test.expected, got)
+		}
+	}
+}
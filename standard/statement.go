@@ -10,7 +10,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Jack-Timothy/sheets-client/category"
 	"github.com/Jack-Timothy/sheets-client/cleanprint"
+	"github.com/Jack-Timothy/sheets-client/date"
 )
 
 type Statement []Transaction
@@ -29,6 +31,20 @@ func (s Statement) Print(withIndex bool) {
 	}
 	for i, t := range s {
 		statementStrings = append(statementStrings, t.makePrintableLine(i, withIndex))
+		statementStrings = append(statementStrings, t.makePrintableSplitLines(withIndex)...)
+	}
+	cleanprint.Print(statementStrings)
+}
+
+// printIndexes prints the transactions at the given indexes along with their
+// indexes in s.
+func (s Statement) printIndexes(indexes ...int) {
+	statementStrings := [][]string{
+		append([]string{}, columnTitles...),
+	}
+	for _, i := range indexes {
+		statementStrings = append(statementStrings, s[i].makePrintableLine(i, true))
+		statementStrings = append(statementStrings, s[i].makePrintableSplitLines(true)...)
 	}
 	cleanprint.Print(statementStrings)
 }
@@ -44,7 +60,13 @@ func getUserInput() (userInput string, err error) {
 	return userInput, nil
 }
 
-func (s *Statement) AcceptUserEdits() error {
+// AcceptUserEdits lets the user edit s until they accept it, picking
+// categories from categories.
+func (s *Statement) AcceptUserEdits(categories category.List) error {
+	if err := s.confirmNearDuplicates(); err != nil {
+		return fmt.Errorf("failed to confirm possible duplicates: %w", err)
+	}
+
 	fmt.Println("Statement:")
 	s.Print(true)
 	for {
@@ -53,6 +75,7 @@ func (s *Statement) AcceptUserEdits() error {
 		fmt.Println("- Enter 'add' to add a new transaction.")
 		fmt.Println("- Enter 'delete <TRANSACTION_INDEX>' to delete a transaction.")
 		fmt.Println("- Enter 'edit <TRANSACTION_INDEX>' to edit a transaction.")
+		fmt.Println("- Enter 'split <TRANSACTION_INDEX>' to divide a transaction between categories.")
 
 		selectedAction, err := getUserInput()
 		if err != nil {
@@ -64,7 +87,7 @@ func (s *Statement) AcceptUserEdits() error {
 			return nil
 		}
 
-		err = s.editBasedOnUserInput(selectedAction)
+		err = s.editBasedOnUserInput(selectedAction, categories)
 		if err != nil {
 			log.Printf("Error editing based on user input: %v", err)
 			continue
@@ -75,7 +98,7 @@ func (s *Statement) AcceptUserEdits() error {
 	}
 }
 
-func (s *Statement) editBasedOnUserInput(input string) error {
+func (s *Statement) editBasedOnUserInput(input string, categories category.List) error {
 	frags := strings.Split(input, " ")
 	if len(frags) == 0 {
 		return errors.New("user input is empty")
@@ -84,7 +107,7 @@ func (s *Statement) editBasedOnUserInput(input string) error {
 
 	switch selectedAction {
 	case "add":
-		if err := s.handleUserAddingTransaction(); err != nil {
+		if err := s.handleUserAddingTransaction(categories); err != nil {
 			return fmt.Errorf("failed to handle user adding transaction: %w", err)
 		}
 	case "delete":
@@ -92,17 +115,21 @@ func (s *Statement) editBasedOnUserInput(input string) error {
 			return fmt.Errorf("failed to handle user deleting transaction: %w", err)
 		}
 	case "edit":
-		if err := s.handleUserEditingTransaction(input); err != nil {
+		if err := s.handleUserEditingTransaction(input, categories); err != nil {
 			return fmt.Errorf("failed to handle user editing transaction: %w", err)
 		}
+	case "split":
+		if err := s.handleUserSplittingTransaction(input, categories); err != nil {
+			return fmt.Errorf("failed to handle user splitting transaction: %w", err)
+		}
 	default:
 		return fmt.Errorf("'%s' is not a valid action", selectedAction)
 	}
 	return nil
 }
 
-func (s *Statement) handleUserAddingTransaction() error {
-	t, err := getSingleTransactionFromUser()
+func (s *Statement) handleUserAddingTransaction(categories category.List) error {
+	t, err := getSingleTransactionFromUser(categories)
 	if err != nil {
 		return fmt.Errorf("failed to get single transaction from user: %w", err)
 	}
@@ -115,11 +142,11 @@ func (s *Statement) handleUserAddingTransaction() error {
 }
 
 func (s *Statement) addTransaction(t Transaction) error {
-	*s = append(*s, t)
-	err := s.sort()
-	if err != nil {
-		return fmt.Errorf("failed to sort statement: %w", err)
+	if t.Date.IsZero() {
+		return errors.New("transaction has no date")
 	}
+	*s = append(*s, t)
+	s.sort()
 	return nil
 }
 
@@ -157,7 +184,7 @@ func (s *Statement) getTransactionWithIndex(index int) (*Transaction, error) {
 	return &(*s)[index], nil
 }
 
-func (s *Statement) handleUserEditingTransaction(input string) error {
+func (s *Statement) handleUserEditingTransaction(input string, categories category.List) error {
 	input = strings.TrimPrefix(input, "edit")
 	input = strings.TrimSpace(input)
 	indexToEdit, err := strconv.ParseUint(input, 10, bitsPerWord)
@@ -180,14 +207,17 @@ func (s *Statement) handleUserEditingTransaction(input string) error {
 		return fmt.Errorf("failed to get user input for Date: %w", err)
 	}
 	if dateInput != "" {
-		if err = validateDateString(dateInput); err != nil {
-			return fmt.Errorf("invalid date format: %w", err)
+		d, err := date.Parse(dateInput)
+		if err != nil {
+			return fmt.Errorf("invalid date: %w", err)
 		}
-		tr.Date = dateInput
+		tr.Date = d
 	}
 
 	// edit Category
-	if err = tr.getCategoryFromUser(); err != nil {
+	if len(tr.Splits) > 0 {
+		fmt.Println("The categories of a split transaction are those of its split lines. Use split to change them.")
+	} else if err = tr.getCategoryFromUser(categories); err != nil {
 		return fmt.Errorf("failed to get category from user: %w", err)
 	}
 
@@ -205,27 +235,57 @@ func (s *Statement) handleUserEditingTransaction(input string) error {
 	if err = tr.getAmountFromUser(); err != nil {
 		return fmt.Errorf("failed to get amount from user: %w", err)
 	}
+	if len(tr.Splits) > 0 && tr.SetSplits(tr.Splits) != nil {
+		tr.Splits = nil
+		fmt.Println("The split lines no longer add up to the amount, so the split was removed.")
+	}
 
 	fmt.Println("Resulting transaction data after edits:")
 	tr.printWithHeadings()
 	return nil
 }
 
-func (s *Statement) sort() error {
-	for i, t := range *s {
-		if err := validateDateString(t.Date); err != nil {
-			return fmt.Errorf("transaction with index %d has invalid date string", i)
+func (s *Statement) sort() {
+	sort.SliceStable(*s, func(x, y int) bool {
+		return (*s)[x].Date.Before((*s)[y].Date)
+	})
+}
+
+// NormalizeDescriptions replaces each description with normalize's cleaned
+// up version of it, keeping the bank's text in the provenance's
+// OriginalDescription.
+func (s Statement) NormalizeDescriptions(normalize func(description string) string) {
+	for i := range s {
+		t := &s[i]
+		if t.Provenance.OriginalDescription == "" {
+			t.Provenance.OriginalDescription = t.Description
 		}
+		t.Description = normalize(t.Provenance.OriginalDescription)
 	}
-	sort.Slice(*s, func(x, y int) bool {
-		return isDateXBeforeDateY((*s)[x].Date, (*s)[y].Date)
-	})
-	return nil
 }
 
+// Between returns the transactions of s dated from from to to, inclusive.
+// A zero from or to leaves that end of the range open.
+func (s Statement) Between(from, to date.Date) Statement {
+	filtered := make(Statement, 0, len(s))
+	for _, t := range s {
+		if !from.IsZero() && t.Date.Before(from) {
+			continue
+		}
+		if !to.IsZero() && t.Date.After(to) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	return filtered
+}
+
+// GetRawData returns a row per line of s, split transactions taking a row
+// per split.
 func (s *Statement) GetRawData() [][]interface{} {
-	rawData := make([][]interface{}, 0, len(*s))
-	for _, t := range *s {
+	lines := s.Lines()
+	rawData := make([][]interface{}, 0, len(lines))
+	for _, t := range lines {
 		rawData = append(rawData, t.getRawData())
 	}
 	return rawData
@@ -5,26 +5,70 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
+
+	"github.com/Jack-Timothy/sheets-client/category"
+	"github.com/Jack-Timothy/sheets-client/classify"
+	"github.com/Jack-Timothy/sheets-client/date"
+	"github.com/Jack-Timothy/sheets-client/money"
 )
 
+// Transaction is a single line of a standard statement.
 type Transaction struct {
-	Date        string
-	Category    string
+	Date     date.Date
+	Category string
+	// Description is the cleaned up description shown to the user and
+	// written to the sheet.
 	Description string
-	Amount      float64
+	// Amount is positive for money spent and negative for money received.
+	Amount money.Amount
+	// Balance is the account balance after the transaction, or nil when the
+	// source does not report one.
+	Balance *money.Amount
+	// Source is the name of the importer that read the transaction, e.g.
+	// "Chase credit card", or "manual" for one the user entered. It is part
+	// of the import ID.
+	Source string
+	// ImportID identifies the transaction in the sheet so that importing
+	// the same file twice does not duplicate rows.
+	ImportID string
+	// Fingerprint identifies the transaction across overlapping source
+	// files. Importers that have nothing better leave it empty.
+	Fingerprint string
+	// Splits, if any, divide the transaction between categories.
+	Splits []Split
+	// SplitID links the rows of one split transaction in the sheet.
+	SplitID string
+	// RefundOf is the import ID of the purchase a refund gives money back
+	// for.
+	RefundOf string
+	// Account is the name of the configured account the transaction was
+	// made from, if known.
+	Account string
+
+	// LegacyImportID is the import ID the transaction had before its
+	// account was part of import IDs.
+	LegacyImportID string
+	// Provenance records what the source said about the transaction beyond
+	// the fields the sheet needs.
+	Provenance Provenance
 }
 
 func (t *Transaction) getRawData() []interface{} {
 	return []interface{}{
-		t.Date,
+		t.Date.String(),
 		t.Category,
 		t.Description,
-		t.Amount,
+		t.Amount.Float64(),
+		t.ImportID,
+		t.SplitID,
+		t.RefundOf,
+		t.Account,
 	}
 }
 
-func (t *Transaction) GetDescriptionAndCategoryFromUser() (skip bool, err error) {
+// GetDescriptionAndCategoryFromUser asks for a description and a category.
+// Any suggestions are offered as one-key choices ahead of the full menu.
+func (t *Transaction) GetDescriptionAndCategoryFromUser(categories category.List, suggestions []classify.Suggestion) (skip bool, err error) {
 	skip, err = t.getDescriptionFromUserWithOptions()
 	if err != nil {
 		return false, fmt.Errorf("failed to get description from user: %w", err)
@@ -33,7 +77,11 @@ func (t *Transaction) GetDescriptionAndCategoryFromUser() (skip bool, err error)
 		return skip, nil
 	}
 
-	err = t.getCategoryFromUser()
+	if len(suggestions) == 0 {
+		err = t.getCategoryFromUser(categories)
+	} else {
+		err = t.getCategoryFromUserWithSuggestions(categories, suggestions)
+	}
 	if err != nil {
 		return false, fmt.Errorf("failed to get category from user: %w", err)
 	}
@@ -74,18 +122,26 @@ func (t *Transaction) getDescriptionFromUser() error {
 
 func (t *Transaction) getAmountFromUser() error {
 	fmt.Printf("Please enter the amount of the transaction.\n")
-	_, err := fmt.Scanf("%f\n", &t.Amount)
-	return err
+	amountInput, err := getUserInput()
+	if err != nil {
+		return fmt.Errorf("failed to get user input: %w", err)
+	}
+	amount, err := money.Parse(amountInput)
+	if err != nil {
+		return fmt.Errorf("failed to parse amount: %w", err)
+	}
+	t.Amount = amount
+	return nil
 }
 
-func (t *Transaction) getCategoryFromUser() error {
-	fmt.Printf("Please enter the enumeration of this transaction's category. Options are:\n")
-	categories := []string{
-		"Rent", "Utilities", "Groceries/Toiletries", "Food/Drinks Out", "Gas",
-		"Other (Need)", "Other (Want)", "Gift Giving", "Donations",
+func (t *Transaction) getCategoryFromUser(configured category.List) error {
+	categories := configured.Selectable()
+	if len(categories) == 0 {
+		return errors.New("no categories are configured")
 	}
-	for i, category := range categories {
-		fmt.Printf("%d. %s ", i+1, category)
+	fmt.Printf("Please enter the enumeration of this transaction's category. Options are:\n")
+	for i, c := range categories {
+		fmt.Printf("%d. %s ", i+1, c.Label())
 	}
 	fmt.Printf("\n")
 
@@ -98,79 +154,65 @@ func (t *Transaction) getCategoryFromUser() error {
 		return fmt.Errorf("received invalid category enumeration %d", categoryEnum)
 	}
 
-	t.Category = categories[categoryEnum-1]
+	t.Category = categories[categoryEnum-1].Name
 	return nil
 }
 
-func (t *Transaction) getDateFromUser() error {
-	fmt.Printf("Please enter the date of the transaction with the format MM/DD/YYYY.\n")
-	date, err := getUserInput()
+// suggestionKeys are the keys that pick a suggested category.
+const suggestionKeys = "abcdefghi"
+
+func (t *Transaction) getCategoryFromUserWithSuggestions(configured category.List, suggestions []classify.Suggestion) error {
+	categories := configured.Selectable()
+	if len(suggestions) > len(suggestionKeys) {
+		suggestions = suggestions[:len(suggestionKeys)]
+	}
+	fmt.Printf("Suggested categories:\n")
+	for i, s := range suggestions {
+		fmt.Printf("%c. %s (%.0f%%) ", suggestionKeys[i], s.Category, 100*s.Confidence)
+	}
+	fmt.Printf("\nEnter a letter to pick a suggestion, or the enumeration of any category:\n")
+	for i, c := range categories {
+		fmt.Printf("%d. %s ", i+1, c.Label())
+	}
+	fmt.Printf("\n")
+
+	input, err := getUserInput()
 	if err != nil {
 		return fmt.Errorf("failed to get user input: %w", err)
 	}
-	if err = validateDateString(date); err != nil {
-		return fmt.Errorf("invalid date format: %w", err)
+	input = strings.TrimSpace(input)
+	if len(input) == 1 {
+		if i := strings.Index(suggestionKeys, input); i >= 0 && i < len(suggestions) {
+			t.Category = suggestions[i].Category
+			return nil
+		}
+	}
+	categoryEnum, err := strconv.Atoi(input)
+	if err != nil {
+		return fmt.Errorf("'%s' is neither a suggestion letter nor a category enumeration", input)
 	}
-	t.Date = date
+	if categoryEnum > len(categories) || categoryEnum < 1 {
+		return fmt.Errorf("received invalid category enumeration %d", categoryEnum)
+	}
+	t.Category = categories[categoryEnum-1].Name
 	return nil
 }
 
-const bitsPerWord = 32 << (^uint(0) >> 63)
-
-func validateDateString(date string) error {
-	dateElements := strings.Split(date, "/")
-	if len(dateElements) != 3 {
-		return fmt.Errorf("expected 2 slashes, but got %d", len(dateElements)-1)
+func (t *Transaction) getDateFromUser() error {
+	fmt.Printf("Please enter the date of the transaction, e.g. %s.\n", date.Today())
+	dateInput, err := getUserInput()
+	if err != nil {
+		return fmt.Errorf("failed to get user input: %w", err)
 	}
-	for i, dateElement := range dateElements {
-		num, err := strconv.ParseInt(dateElement, 10, bitsPerWord)
-		if err != nil {
-			return fmt.Errorf("failed to parse number with index %d as an integer: %w", i, err)
-		}
-		switch i {
-		case 0: // month must be between 1 and 12
-			if num < 1 || num > 12 {
-				return fmt.Errorf("received %d for month but it must be between 1 and 12", num)
-			}
-		case 1: // day must be between 1 and 31
-			if num < 1 || num > 31 {
-				return fmt.Errorf("received %d for day but it must be between 1 and 31", num)
-			}
-		default: // year just must be positive
-			if num < 1 {
-				return fmt.Errorf("received %d for year but it must be positive", num)
-			}
-		}
+	d, err := date.Parse(dateInput)
+	if err != nil {
+		return fmt.Errorf("invalid date: %w", err)
 	}
+	t.Date = d
 	return nil
 }
 
-func isDateXBeforeDateY(x, y string) bool {
-	xElements := strings.Split(x, "/")
-	yElements := strings.Split(y, "/")
-	// year is first comparison
-	xYear, _ := strconv.ParseInt(xElements[2], 10, bitsPerWord)
-	yYear, _ := strconv.ParseInt(yElements[2], 10, bitsPerWord)
-	if xYear < yYear {
-		return true
-	}
-	if xYear > yYear {
-		return false
-	}
-	// month is next comparison
-	xMonth, _ := strconv.ParseInt(xElements[0], 10, bitsPerWord)
-	yMonth, _ := strconv.ParseInt(yElements[0], 10, bitsPerWord)
-	if xMonth < yMonth {
-		return true
-	}
-	if xMonth > yMonth {
-		return false
-	}
-	// day is final comparison
-	xDay, _ := strconv.ParseInt(xElements[1], 10, bitsPerWord)
-	yDay, _ := strconv.ParseInt(yElements[1], 10, bitsPerWord)
-	return xDay < yDay
-}
+const bitsPerWord = 32 << (^uint(0) >> 63)
 
 func (tr *Transaction) printWithHeadings() {
 	statementCopy := make(Statement, 0, 1)
@@ -179,11 +221,15 @@ func (tr *Transaction) printWithHeadings() {
 }
 
 func (t *Transaction) makePrintableLine(index int, withIndex bool) []string {
+	category := t.Category
+	if len(t.Splits) > 0 {
+		category = fmt.Sprintf("Split (%d lines)", len(t.Splits))
+	}
 	line := []string{
-		t.Date,
-		t.Category,
+		t.Date.String(),
+		category,
 		t.Description,
-		fmt.Sprintf("%f", t.Amount),
+		t.Amount.String(),
 	}
 	if withIndex {
 		line = append([]string{fmt.Sprintf("%d", index)}, line...)
@@ -191,20 +237,37 @@ func (t *Transaction) makePrintableLine(index int, withIndex bool) []string {
 	return line
 }
 
+// makePrintableSplitLines returns a line for each split of t, indented under
+// t's own line.
+func (t *Transaction) makePrintableSplitLines(withIndex bool) [][]string {
+	lines := make([][]string, 0, len(t.Splits))
+	for _, split := range t.Splits {
+		description := split.Description
+		if description == "" {
+			description = t.Description
+		}
+		line := []string{"", "  " + split.Category, "  " + description, split.Amount.String()}
+		if withIndex {
+			line = append([]string{""}, line...)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 func buildTestTransaction(i int) (t Transaction) {
-	now := time.Now()
-	t.Date = fmt.Sprintf("%d/%d/%d", now.Month(), now.Day(), now.Year())
+	t.Date = date.Today()
 	t.Category = fmt.Sprintf("Test Category %d", i)
 	t.Description = fmt.Sprintf("Test Description %d", i)
-	t.Amount = float64(20 + i)
+	t.Amount = money.FromCents(int64(2000 + 100*i))
 	return t
 }
 
-func getSingleTransactionFromUser() (t Transaction, err error) {
+func getSingleTransactionFromUser(categories category.List) (t Transaction, err error) {
 	if err = t.getDateFromUser(); err != nil {
 		return t, fmt.Errorf("failed to get date from user: %w", err)
 	}
-	if err = t.getCategoryFromUser(); err != nil {
+	if err = t.getCategoryFromUser(categories); err != nil {
 		return t, fmt.Errorf("failed to get category from user: %w", err)
 	}
 	if err = t.getDescriptionFromUser(); err != nil {
@@ -0,0 +1,89 @@
+package standard
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultTransferDays is how many days apart the two sides of a transfer
+// may be dated, since the receiving account often posts it a few days late.
+const DefaultTransferDays = 5
+
+// transferHints are words in the description or type of a transaction that
+// suggest money moving between the user's own accounts, e.g. a credit card
+// payment.
+var transferHints = []string{
+	"payment", "autopay", "epay", "pymt", "transfer", "xfer", "acct_xfer", "loan_pmt",
+}
+
+// LooksLikeTransfer reports whether t's description or type hints that it
+// moves money between accounts.
+func (t *Transaction) LooksLikeTransfer() bool {
+	text := strings.ToLower(t.Description + " " + t.Provenance.OriginalDescription + " " + t.Provenance.ItemType)
+	for _, hint := range transferHints {
+		if strings.Contains(text, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkTransfers pairs transactions that move money between two of the
+// user's accounts: one leaving an account and the same amount arriving in
+// another within maxDays, at least one of them hinting at a transfer. Both
+// sides of each pair are filed under category. It returns the number of
+// pairs and the transactions that look like transfers but found no pair.
+func (s Statement) MarkTransfers(category string, maxDays int) (numPairs int, unmatched Statement) {
+	type candidate struct {
+		from, to int
+		days     int
+	}
+	received := map[int64][]int{}
+	for j := range s {
+		if s[j].Amount.Cents() < 0 {
+			received[-s[j].Amount.Cents()] = append(received[-s[j].Amount.Cents()], j)
+		}
+	}
+	var candidates []candidate
+	for i := range s {
+		if s[i].Amount.Cents() <= 0 {
+			continue
+		}
+		for _, j := range received[s[i].Amount.Cents()] {
+			a, b := &s[i], &s[j]
+			if a.accountKey() == b.accountKey() {
+				continue
+			}
+			if a.Category != "" || b.Category != "" || (!a.LooksLikeTransfer() && !b.LooksLikeTransfer()) {
+				continue
+			}
+			days := a.Date.DaysUntil(b.Date)
+			if days < 0 {
+				days = -days
+			}
+			if days <= maxDays {
+				candidates = append(candidates, candidate{from: i, to: j, days: days})
+			}
+		}
+	}
+	// The closest dates are paired first.
+	sort.SliceStable(candidates, func(x, y int) bool {
+		return candidates[x].days < candidates[y].days
+	})
+
+	paired := make([]bool, len(s))
+	for _, c := range candidates {
+		if paired[c.from] || paired[c.to] {
+			continue
+		}
+		paired[c.from], paired[c.to] = true, true
+		s[c.from].Category, s[c.to].Category = category, category
+		numPairs++
+	}
+	for i := range s {
+		if !paired[i] && s[i].Category == "" && s[i].LooksLikeTransfer() {
+			unmatched = append(unmatched, s[i])
+		}
+	}
+	return numPairs, unmatched
+}
@@ -0,0 +1,67 @@
+package standard
+
+import "testing"
+
+func TestLooksLikeTransfer(t *testing.T) {
+	tests := []struct {
+		t        Transaction
+		expected bool
+	}{
+		{Transaction{Description: "AUTOPAY PAYMENT - THANK YOU"}, true},
+		{Transaction{Description: "Online Transfer to SAV 1234"}, true},
+		{Transaction{Description: "CHASE CREDIT CRD", Provenance: Provenance{ItemType: "ACCT_XFER"}}, true},
+		{Transaction{Description: "Visa", Provenance: Provenance{OriginalDescription: "VISA EPAY 0042"}}, true},
+		{Transaction{Description: "COFFEE SHOP"}, false},
+	}
+	for _, test := range tests {
+		if got := test.t.LooksLikeTransfer(); got != test.expected {
+			t.Errorf("'%s': expected %v, got %v", test.t.Description, test.expected, got)
+		}
+	}
+}
+
+func TestMarkTransfers(t *testing.T) {
+	s := Statement{
+		// A card payment leaving checking and arriving on the card two
+		// days later.
+		{Date: mustDate(t, "2024-01-10"), Description: "CHASE CREDIT CRD AUTOPAY", Amount: 50000, Account: "Checking"},
+		{Date: mustDate(t, "2024-01-12"), Description: "AUTOMATIC PAYMENT - THANK YOU", Amount: -50000, Account: "Visa"},
+		// The same amount refunded on the same account is no transfer.
+		{Date: mustDate(t, "2024-01-11"), Description: "STORE", Amount: 50000, Account: "Visa", Category: "Home"},
+		{Date: mustDate(t, "2024-01-11"), Description: "STORE REFUND", Amount: -20000, Account: "Visa"},
+		{Date: mustDate(t, "2024-01-11"), Description: "STORE", Amount: 20000, Account: "Visa"},
+		// A payment whose other side is not in the statement.
+		{Date: mustDate(t, "2024-01-15"), Description: "AMEX EPAYMENT", Amount: 12345, Account: "Checking"},
+		// Too far apart.
+		{Date: mustDate(t, "2024-01-01"), Description: "TRANSFER TO SAVINGS", Amount: 7000, Account: "Checking"},
+		{Date: mustDate(t, "2024-01-20"), Description: "TRANSFER FROM CHECKING", Amount: -7000, Account: "Savings"},
+	}
+	numPairs, unmatched := s.MarkTransfers("Transfer", DefaultTransferDays)
+	if numPairs != 1 {
+		t.Errorf("expected 1 pair, got %d", numPairs)
+	}
+	expectedCategories := []string{"Transfer", "Transfer", "Home", "", "", "", "", ""}
+	for i, category := range expectedCategories {
+		if s[i].Category != category {
+			t.Errorf("transaction %d: expected category '%s', got '%s'", i, category, s[i].Category)
+		}
+	}
+	if len(unmatched) != 3 {
+		t.Errorf("expected 3 unmatched transfers, got %d", len(unmatched))
+	}
+}
+
+func TestMarkTransfersPairsClosestDates(t *testing.T) {
+	s := Statement{
+		{Date: mustDate(t, "2024-01-10"), Description: "TRANSFER", Amount: 1000, Account: "Checking"},
+		{Date: mustDate(t, "2024-01-14"), Description: "TRANSFER", Amount: -1000, Account: "Savings"},
+		{Date: mustDate(t, "2024-01-13"), Description: "TRANSFER", Amount: 1000, Account: "Checking"},
+	}
+	numPairs, unmatched := s.MarkTransfers("Transfer", DefaultTransferDays)
+	if numPairs != 1 || len(unmatched) != 1 {
+		t.Fatalf("expected 1 pair and 1 unmatched, got %d and %d", numPairs, len(unmatched))
+	}
+	if s[0].Category != "" || s[2].Category != "Transfer" {
+		t.Errorf("expected the transfer one day from its other side to be paired")
+	}
+}